@@ -2,9 +2,8 @@ package agent
 
 import (
 	"errors"
-	"fmt"
 
-	"github.com/darkhz/bluetuith/ui"
+	"github.com/darkhz/bluetuith/bluez"
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
 )
@@ -117,98 +116,84 @@ func CallAgentManager(method string, args ...interface{}) *dbus.Call {
 	return agent.conn.Object(AgentBluezName, AgentManagerPath).Call(AgentManagerIface+"."+method, 0, args...)
 }
 
-// RequestPinCode returns the default pincode.
+// RequestPinCode returns the default pincode, if the handler provides it.
 func (a *Agent) RequestPinCode(path dbus.ObjectPath) (string, *dbus.Error) {
+	device, err := handler.GetDevice(string(path))
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+
+	if !handler.RequestPinCode(device, a.pinCode) {
+		return "", dbus.MakeFailedError(errors.New("Cancelled"))
+	}
+
 	return a.pinCode, nil
 }
 
-// RequestPasskey returns the default passkey.
+// RequestPasskey returns the default passkey, if the handler provides it.
 func (a *Agent) RequestPasskey(path dbus.ObjectPath) (uint32, *dbus.Error) {
+	device, err := handler.GetDevice(string(path))
+	if err != nil {
+		return 0, dbus.MakeFailedError(err)
+	}
+
+	if !handler.RequestPasskey(device, a.passKey) {
+		return 0, dbus.MakeFailedError(errors.New("Cancelled"))
+	}
+
 	return a.passKey, nil
 }
 
 // DisplayPinCode shows a notification with the pincode.
 func (a *Agent) DisplayPinCode(path dbus.ObjectPath, pincode string) *dbus.Error {
-	device, err := ui.GetDeviceFromPath(string(path))
+	device, err := handler.GetDevice(string(path))
 	if err != nil {
 		return dbus.MakeFailedError(err)
 	}
 
-	msg := fmt.Sprintf(
-		"The pincode for [::bu]%s[-:-:-] is:\n\n[::b]%s[-:-:-]",
-		device.Name, pincode,
-	)
-
-	ui.NewDisplayModal("pincode", "Pin Code", msg)
+	handler.DisplayPinCode(device, pincode)
 
 	return nil
 }
 
 // DisplayPasskey shows a notification with the passkey.
 func (a *Agent) DisplayPasskey(path dbus.ObjectPath, passkey uint32, entered uint16) *dbus.Error {
-	device, err := ui.GetDeviceFromPath(string(path))
+	device, err := handler.GetDevice(string(path))
 	if err != nil {
 		return dbus.MakeFailedError(err)
 	}
 
-	msg := fmt.Sprintf(
-		"The passkey for [::bu]%s[-:-:-] is:\n\n[::b]%d[-:-:-]",
-		device.Name, passkey,
-	)
-	if entered > 0 {
-		msg += fmt.Sprintf("\n\nYou have entered %d", entered)
-	}
-
-	ui.NewDisplayModal("passkey-display", "Passkey Display", msg)
+	handler.DisplayPasskey(device, passkey, entered)
 
 	return nil
 }
 
 // RequestConfirmation shows the passkey and asks for confirmation.
 func (a *Agent) RequestConfirmation(path dbus.ObjectPath, passkey uint32) *dbus.Error {
-	device, err := ui.GetDeviceFromPath(string(path))
+	device, err := handler.GetDevice(string(path))
 	if err != nil {
 		return dbus.MakeFailedError(err)
 	}
 
-	msg := fmt.Sprintf(
-		"Confirm passkey for [::bu]%s[-:-:-] is \n\n[::b]%d[-:-:-]",
-		device.Name, passkey,
-	)
-
-	reply := ui.NewConfirmModal("passkey-confirm", "Passkey Confirmation", msg)
-	if reply != "y" {
+	if !handler.ConfirmPasskey(device, passkey) {
 		return dbus.MakeFailedError(errors.New("Cancelled"))
 	}
 
-	err = ui.SetTrusted(string(path), true)
-	if err != nil {
-		return dbus.MakeFailedError(err)
-	}
-
-	return nil
+	return setTrusted(device)
 }
 
 // RequestAuthorization asks for confirmation before pairing.
 func (a *Agent) RequestAuthorization(path dbus.ObjectPath) *dbus.Error {
-	device, err := ui.GetDeviceFromPath(string(path))
+	device, err := handler.GetDevice(string(path))
 	if err != nil {
 		return dbus.MakeFailedError(err)
 	}
 
-	msg := fmt.Sprintf("Confirm pairing with [::bu]%s[-:-:-]", device.Name)
-
-	reply := ui.NewConfirmModal("pairing-confirm", "Pairing Confirmation", msg)
-	if reply != "y" {
+	if !handler.ConfirmPairing(device) {
 		return dbus.MakeFailedError(errors.New("Cancelled"))
 	}
 
-	err = ui.SetTrusted(string(path), true)
-	if err != nil {
-		return dbus.MakeFailedError(err)
-	}
-
-	return nil
+	return setTrusted(device)
 }
 
 // AuthorizeService asks for confirmation before authorizing a service UUID.
@@ -218,9 +203,7 @@ func (a *Agent) AuthorizeService(device dbus.ObjectPath, uuid string) *dbus.Erro
 		return nil
 	}
 
-	msg := fmt.Sprintf("Authorize service %s (y/n/a)", uuid)
-
-	reply := ui.SetInput(msg)
+	reply := handler.AuthorizeService(device, uuid)
 	switch reply {
 	case "a":
 		alwaysAuthorize = true
@@ -242,3 +225,16 @@ func (a *Agent) Cancel() *dbus.Error {
 func (a *Agent) Release() *dbus.Error {
 	return nil
 }
+
+// setTrusted marks the device as trusted, if the handler allows it.
+func setTrusted(device bluez.Device) *dbus.Error {
+	if !handler.TrustDevice(device) {
+		return nil
+	}
+
+	if err := handler.Bluez().SetDeviceProperty(device.Path, "Trusted", true); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+
+	return nil
+}
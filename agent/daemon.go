@@ -0,0 +1,317 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/notify"
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+)
+
+// The different policies of the daemon, which decide how pairing
+// and file transfer requests are answered.
+const (
+	PolicyTrusted = "trusted"
+	PolicyAccept  = "accept"
+	PolicyReject  = "reject"
+)
+
+// transferTimeout is the time to wait for a file transfer to progress,
+// before it is cancelled.
+const transferTimeout = time.Minute
+
+// DaemonHandler answers agent requests without a UI, according to
+// the configured pairing and receive policies. All decisions are
+// logged, and sent as desktop notifications.
+type DaemonHandler struct {
+	bluez *bluez.Bluez
+	obex  *bluez.Obex
+
+	pairing, receive string
+	trust            bool
+
+	logger *log.Logger
+}
+
+// NewDaemonHandler returns a new DaemonHandler.
+func NewDaemonHandler(b *bluez.Bluez, o *bluez.Obex) (*DaemonHandler, error) {
+	var writer io.Writer = os.Stderr
+
+	if logFile := cmd.GetProperty("daemon-log"); logFile != "" {
+		file, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, errors.Wrap(err, "Cannot open log file")
+		}
+
+		if err := file.Chmod(0600); err != nil {
+			file.Close()
+			return nil, errors.Wrap(err, "Cannot set log file permissions")
+		}
+
+		writer = file
+	}
+
+	return &DaemonHandler{
+		bluez:   b,
+		obex:    o,
+		pairing: daemonPolicy("daemon-pairing"),
+		receive: daemonPolicy("daemon-receive"),
+		trust:   cmd.IsPropertyEnabled("daemon-trust"),
+		logger:  log.New(writer, "bluetuith: ", log.LstdFlags),
+	}, nil
+}
+
+// Bluez returns the bluez DBus connection.
+func (d *DaemonHandler) Bluez() *bluez.Bluez {
+	return d.bluez
+}
+
+// Obex returns the bluez OBEX DBus connection.
+func (d *DaemonHandler) Obex() *bluez.Obex {
+	return d.obex
+}
+
+// GetDevice returns the device with its current properties.
+// Since the device store is not refreshed without the UI, the
+// properties are queried from bluez directly.
+func (d *DaemonHandler) GetDevice(devicePath string) (bluez.Device, error) {
+	var devices []bluez.Device
+
+	props, err := d.bluez.GetDeviceProperties(devicePath)
+	if err != nil {
+		return bluez.Device{}, errors.Wrap(err, "Device not found")
+	}
+
+	if err := d.bluez.ConvertToDevice(devicePath, props, &devices); err != nil {
+		return bluez.Device{}, err
+	}
+
+	return devices[0], nil
+}
+
+// DisplayPinCode notifies the pincode. The pincode is not logged.
+func (d *DaemonHandler) DisplayPinCode(device bluez.Device, pincode string) {
+	d.logger.Printf("Pin Code: Displayed the pincode for %s", deviceName(device))
+	d.sendNotification("Pin Code", fmt.Sprintf("The pincode for %s is %s", deviceName(device), pincode))
+}
+
+// DisplayPasskey notifies the passkey. The passkey is not logged.
+func (d *DaemonHandler) DisplayPasskey(device bluez.Device, passkey uint32, entered uint16) {
+	d.logger.Printf("Passkey Display: Displayed the passkey for %s", deviceName(device))
+	d.sendNotification("Passkey Display", fmt.Sprintf("The passkey for %s is %d", deviceName(device), passkey))
+}
+
+// RequestPinCode provides the default pincode according to the pairing policy.
+func (d *DaemonHandler) RequestPinCode(device bluez.Device, pincode string) bool {
+	return d.decide("Pin Code Request", d.pairing, device.Trusted,
+		"pincode for "+deviceName(device),
+	)
+}
+
+// RequestPasskey provides the default passkey according to the pairing policy.
+func (d *DaemonHandler) RequestPasskey(device bluez.Device, passkey uint32) bool {
+	return d.decide("Passkey Request", d.pairing, device.Trusted,
+		"passkey for "+deviceName(device),
+	)
+}
+
+// ConfirmPasskey confirms the passkey according to the pairing policy.
+func (d *DaemonHandler) ConfirmPasskey(device bluez.Device, passkey uint32) bool {
+	return d.decide("Passkey Confirmation", d.pairing, device.Trusted,
+		"passkey for "+deviceName(device),
+	)
+}
+
+// ConfirmPairing confirms pairing according to the pairing policy.
+func (d *DaemonHandler) ConfirmPairing(device bluez.Device) bool {
+	return d.decide("Pairing Confirmation", d.pairing, device.Trusted,
+		"pairing with "+deviceName(device),
+	)
+}
+
+// TrustDevice returns whether paired devices are marked as trusted,
+// which is enabled with the "daemon-trust" option.
+func (d *DaemonHandler) TrustDevice(device bluez.Device) bool {
+	if d.trust {
+		d.logger.Printf("Trusted %s", deviceName(device))
+	}
+
+	return d.trust
+}
+
+// AuthorizeService authorizes the service according to the pairing policy.
+// Unlike the UI, all services are never authorized at once, so that each
+// service request is checked against the policy.
+func (d *DaemonHandler) AuthorizeService(devicePath dbus.ObjectPath, uuid string) string {
+	device, err := d.GetDevice(string(devicePath))
+	if err != nil {
+		d.logger.Printf("Rejected service %s: %s", uuid, err)
+		return "n"
+	}
+
+	if !d.decide("Service Authorization", d.pairing, device.Trusted,
+		"service "+uuid+" for "+deviceName(device),
+	) {
+		return "n"
+	}
+
+	return "y"
+}
+
+// AuthorizePush accepts the file according to the receive policy.
+func (d *DaemonHandler) AuthorizePush(address, name string) string {
+	var trusted bool
+
+	if err := d.bluez.RefreshStore(); err == nil {
+		if device, ok := d.bluez.GetDeviceFromAddress(address); ok {
+			trusted = device.Trusted
+			address = deviceName(device)
+		}
+	}
+
+	if !d.decide("File Transfer", d.receive, trusted,
+		"file "+filepath.Base(name)+" from "+address,
+	) {
+		return "n"
+	}
+
+	return "y"
+}
+
+// ReceiveFile waits for the transfer to finish, and moves the
+// received file to the receive directory. The transfer is cancelled
+// if it does not progress within the transfer timeout.
+func (d *DaemonHandler) ReceiveFile(transferPath dbus.ObjectPath, props bluez.ObexTransferProperties, path string) {
+	signals := d.obex.WatchSignal()
+	defer d.obex.Conn().RemoveSignal(signals)
+
+	// The transfer may have finished before the signals were watched.
+	status, err := d.obex.GetTransferStatus(transferPath)
+	if err != nil {
+		status = receivedStatus(path, props)
+	}
+
+	timeout := time.NewTimer(transferTimeout)
+	defer timeout.Stop()
+
+	for status != "complete" && status != "error" {
+		select {
+		case signal, ok := <-signals:
+			if !ok {
+				return
+			}
+
+			switch {
+			case signal.Name == "org.freedesktop.DBus.ObjectManager.InterfacesRemoved":
+				if len(signal.Body) > 0 && signal.Body[0] == transferPath {
+					status = receivedStatus(path, props)
+				}
+
+			case signal.Path == transferPath:
+				transferProps, ok := d.obex.ParseSignalData(signal).(bluez.ObexProperties)
+				if !ok {
+					continue
+				}
+
+				status = transferProps.TransferProperties.Status
+
+				if !timeout.Stop() {
+					<-timeout.C
+				}
+				timeout.Reset(transferTimeout)
+			}
+
+		case <-timeout.C:
+			d.logger.Printf("Transfer of %s timed out", props.Name)
+			d.obex.CancelTransfer(transferPath)
+
+			status = "error"
+		}
+	}
+
+	if status == "error" {
+		d.notify("Transfer failed", props.Name)
+		return
+	}
+
+	userpath, err := cmd.ReceiveDir()
+	if err == nil {
+		err = os.Rename(path, filepath.Join(userpath, filepath.Base(path)))
+	}
+	if err != nil {
+		d.logger.Printf("Cannot save %s: %s", props.Name, err)
+		return
+	}
+
+	d.notify("Transfer complete", props.Name)
+}
+
+// decide returns whether the request is accepted according to the policy,
+// and logs and notifies the decision.
+func (d *DaemonHandler) decide(summary, policy string, trusted bool, request string) bool {
+	var accept bool
+
+	switch policy {
+	case PolicyAccept:
+		accept = true
+
+	case PolicyTrusted:
+		accept = trusted
+	}
+
+	decision := "Rejected"
+	if accept {
+		decision = "Accepted"
+	}
+
+	d.notify(summary, fmt.Sprintf("%s %s (policy: %s)", decision, request, policy))
+
+	return accept
+}
+
+// notify logs the message and sends it as a desktop notification.
+func (d *DaemonHandler) notify(summary, body string) {
+	d.logger.Printf("%s: %s", summary, body)
+	d.sendNotification(summary, body)
+}
+
+// sendNotification sends the message as a desktop notification.
+func (d *DaemonHandler) sendNotification(summary, body string) {
+	if _, err := notify.Send(summary, body, notify.UrgencyNormal); err != nil {
+		d.logger.Printf("%s", err)
+	}
+}
+
+// daemonPolicy returns the policy set for the property,
+// or the default "trusted" policy if it is not set.
+func daemonPolicy(property string) string {
+	policy := cmd.GetProperty(property)
+	if policy == "" {
+		policy = PolicyTrusted
+	}
+
+	return policy
+}
+
+// receivedStatus returns the status of a transfer which was removed, based on
+// whether the received file is complete.
+func receivedStatus(path string, props bluez.ObexTransferProperties) string {
+	info, err := os.Stat(path)
+	if err != nil || (props.Size > 0 && uint64(info.Size()) != props.Size) {
+		return "error"
+	}
+
+	return "complete"
+}
+
+// deviceName returns the name and address of the device.
+func deviceName(device bluez.Device) string {
+	return device.Name + " (" + device.Address + ")"
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/godbus/dbus/v5"
+)
+
+// Handler describes how the pairing and OBEX agents respond to requests.
+type Handler interface {
+	// Bluez returns the bluez DBus connection.
+	Bluez() *bluez.Bluez
+
+	// Obex returns the bluez OBEX DBus connection.
+	Obex() *bluez.Obex
+
+	// GetDevice returns the device with the provided path.
+	GetDevice(devicePath string) (bluez.Device, error)
+
+	// DisplayPinCode displays the pincode of the device.
+	DisplayPinCode(device bluez.Device, pincode string)
+
+	// DisplayPasskey displays the passkey of the device.
+	DisplayPasskey(device bluez.Device, passkey uint32, entered uint16)
+
+	// RequestPinCode returns whether the pincode is provided to the device.
+	RequestPinCode(device bluez.Device, pincode string) bool
+
+	// RequestPasskey returns whether the passkey is provided to the device.
+	RequestPasskey(device bluez.Device, passkey uint32) bool
+
+	// ConfirmPasskey returns whether the passkey of the device is confirmed.
+	ConfirmPasskey(device bluez.Device, passkey uint32) bool
+
+	// ConfirmPairing returns whether pairing with the device is confirmed.
+	ConfirmPairing(device bluez.Device) bool
+
+	// TrustDevice returns whether the device is marked as trusted,
+	// once pairing with the device is confirmed.
+	TrustDevice(device bluez.Device) bool
+
+	// AuthorizeService returns "y" or "a" if the service UUID of
+	// the device is authorized, where "a" authorizes all services.
+	AuthorizeService(devicePath dbus.ObjectPath, uuid string) string
+
+	// AuthorizePush returns "y" or "a" if the file from the device address
+	// is accepted, where "a" accepts all files from the device.
+	AuthorizePush(address, name string) string
+
+	// ReceiveFile monitors the transfer until the file is received.
+	ReceiveFile(transferPath dbus.ObjectPath, props bluez.ObexTransferProperties, path string)
+}
+
+var handler Handler
+
+// SetHandler sets the handler which responds to agent requests.
+// It must be set before the agents are setup.
+func SetHandler(h Handler) {
+	handler = h
+}
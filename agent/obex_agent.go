@@ -4,7 +4,6 @@ import (
 	"errors"
 	"path/filepath"
 
-	"github.com/darkhz/bluetuith/ui"
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
 )
@@ -105,17 +104,16 @@ func CallObexAgentManager(method string, args ...interface{}) *dbus.Call {
 // If the "Accept all" reply is given in response to the confirmation query, the device
 // will be added to a list of known devices and all transfers will be automatically accepted.
 func (o *ObexAgent) AuthorizePush(transferPath dbus.ObjectPath) (string, *dbus.Error) {
-	var msg, reply string
-
-	adapter := ui.UI.Bluez.GetCurrentAdapter()
+	adapter := handler.Bluez().GetCurrentAdapter()
 	if !adapter.Lock.TryAcquire(1) {
 		return "", dbus.MakeFailedError(errors.New("Operation in progress"))
 	}
 
 	sessionPath := dbus.ObjectPath(filepath.Dir(string(transferPath)))
 
-	path, device, transferProps, err := ui.UI.Obex.ReceiveFile(sessionPath, transferPath)
+	path, device, transferProps, err := handler.Obex().ReceiveFile(sessionPath, transferPath)
 	if err != nil {
+		adapter.Lock.Release(1)
 		return "", dbus.MakeFailedError(err)
 	}
 
@@ -125,9 +123,7 @@ func (o *ObexAgent) AuthorizePush(transferPath dbus.ObjectPath) (string, *dbus.E
 		}
 	}
 
-	msg = "Accept file " + filepath.Base(path) + " (y/n/a)?"
-	reply = ui.SetInput(msg)
-	switch reply {
+	switch handler.AuthorizePush(device, path) {
 	case "a":
 		knownDevices = append(knownDevices, device)
 
@@ -135,6 +131,7 @@ func (o *ObexAgent) AuthorizePush(transferPath dbus.ObjectPath) (string, *dbus.E
 		break
 
 	default:
+		adapter.Lock.Release(1)
 		return "", dbus.MakeFailedError(errors.New("Cancelled"))
 	}
 
@@ -142,8 +139,8 @@ SkipAuthentication:
 	go func() {
 		defer adapter.Lock.Release(1)
 
-		ui.StartProgress(transferPath, transferProps, path)
-		ui.UI.Obex.RemoveSession(sessionPath)
+		handler.ReceiveFile(transferPath, transferProps, path)
+		handler.Obex().RemoveSession(sessionPath)
 	}()
 
 	return path, nil
@@ -1,6 +1,8 @@
 package bluez
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/godbus/dbus/v5"
@@ -24,6 +26,32 @@ type Adapter struct {
 	Lock *semaphore.Weighted
 }
 
+// AdapterInfo holds detailed information about a bluetooth adapter.
+type AdapterInfo struct {
+	Name                 string
+	Alias                string
+	Address              string
+	AddressType          string
+	Modalias             string
+	Class                uint32
+	UUIDs                []string
+	Roles                []string
+	ExperimentalFeatures []string
+	Manufacturer         uint16
+	Version              byte
+	DiscoverableTimeout  uint32
+	PairableTimeout      uint32
+
+	hasManufacturer, hasVersion bool
+}
+
+// bluetoothVersions matches the HCI version numbers to the Bluetooth Core
+// Specification versions.
+var bluetoothVersions = []string{
+	"1.0b", "1.1", "1.2", "2.0", "2.1", "3.0", "4.0",
+	"4.1", "4.2", "5.0", "5.1", "5.2", "5.3", "5.4",
+}
+
 // CallAdapter is used to interact with the bluez Adapter dbus interface.
 // https://git.kernel.org/pub/scm/bluetooth/bluez.git/tree/doc/adapter-api.txt
 func (b *Bluez) CallAdapter(adapter, method string, flags dbus.Flags, args ...interface{}) *dbus.Call {
@@ -37,6 +65,45 @@ func (b *Bluez) StartDiscovery(adapter string) error {
 	return b.CallAdapter(adapter, "StartDiscovery", 0).Store()
 }
 
+// DiscoveryFilter describes the filter applied to the adapter
+// during device discovery.
+type DiscoveryFilter struct {
+	Transport     string
+	RSSI          int16
+	Pathloss      uint16
+	UUIDs         []string
+	Pattern       string
+	DuplicateData bool
+}
+
+// SetDiscoveryFilter sets the discovery filter for the adapter.
+func (b *Bluez) SetDiscoveryFilter(adapter string, filter DiscoveryFilter) error {
+	if filter.RSSI != 0 && filter.Pathloss != 0 {
+		return errors.New("RSSI and Pathloss cannot be set together")
+	}
+
+	filterMap := make(map[string]interface{})
+
+	if filter.Transport != "" && filter.Transport != "auto" {
+		filterMap["Transport"] = filter.Transport
+	}
+	if filter.RSSI != 0 {
+		filterMap["RSSI"] = filter.RSSI
+	}
+	if filter.Pathloss != 0 {
+		filterMap["Pathloss"] = filter.Pathloss
+	}
+	if filter.UUIDs != nil {
+		filterMap["UUIDs"] = filter.UUIDs
+	}
+	if filter.Pattern != "" {
+		filterMap["Pattern"] = filter.Pattern
+	}
+	filterMap["DuplicateData"] = filter.DuplicateData
+
+	return b.CallAdapter(adapter, "SetDiscoveryFilter", 0, filterMap).Store()
+}
+
 // StopDiscovery will stop the  "discovering" mode, which means the bluetooth device will
 // no longer be able to discover other bluetooth devices that are in pairing mode.
 func (b *Bluez) StopDiscovery(adapter string) error {
@@ -100,6 +167,10 @@ func (b *Bluez) GetAdapters() []Adapter {
 		adapters = append(adapters, store.Adapter)
 	}
 
+	sort.Slice(adapters, func(i, j int) bool {
+		return adapters[i].Path < adapters[j].Path
+	})
+
 	return adapters
 }
 
@@ -163,6 +234,47 @@ func (b *Bluez) GetAdapterProperties(adapterPath string) (map[string]dbus.Varian
 	return result, nil
 }
 
+// GetAdapterInfo returns detailed information about a bluetooth adapter.
+func (b *Bluez) GetAdapterInfo(adapterPath string) (AdapterInfo, error) {
+	var info AdapterInfo
+
+	props, err := b.GetAdapterProperties(adapterPath)
+	if err != nil {
+		return info, err
+	}
+
+	if err := DecodeVariantMap(props, &info, "Address"); err != nil {
+		return info, err
+	}
+
+	_, info.hasManufacturer = props["Manufacturer"]
+	_, info.hasVersion = props["Version"]
+
+	return info, nil
+}
+
+// VersionName returns the Bluetooth Core Specification version of the adapter.
+func (a AdapterInfo) VersionName() string {
+	if !a.hasVersion {
+		return ""
+	}
+
+	if int(a.Version) >= len(bluetoothVersions) {
+		return fmt.Sprintf("Unknown (0x%02x)", a.Version)
+	}
+
+	return bluetoothVersions[a.Version]
+}
+
+// ManufacturerName returns the name of the adapter's manufacturer.
+func (a AdapterInfo) ManufacturerName() string {
+	if !a.hasManufacturer {
+		return ""
+	}
+
+	return CompanyName(uint64(a.Manufacturer))
+}
+
 // SetAdapterProperty can be used to set certain properties for a bluetooth adapter.
 func (b *Bluez) SetAdapterProperty(adapterPath, key string, value interface{}) error {
 	path := dbus.ObjectPath(adapterPath)
@@ -0,0 +1,321 @@
+package bluez
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+// Advertisement holds the advertising data of a LE device.
+type Advertisement struct {
+	ManufacturerData map[uint16][]byte
+	ServiceData      map[string][]byte
+	Flags            []byte
+
+	TxPower    int16
+	HasTxPower bool
+
+	Appearance    uint16
+	HasAppearance bool
+}
+
+// AdvertisingField holds a raw and decoded advertising data field.
+type AdvertisingField struct {
+	Type    string
+	Name    string
+	Raw     string
+	Decoded string
+}
+
+// eddystoneService is the service class of the Eddystone beacon protocol.
+const eddystoneService = 0xfeaa
+
+var (
+	// advertisingFlags matches the bits of the advertising flags to their descriptions.
+	advertisingFlags = []string{
+		"LE Limited Discoverable",
+		"LE General Discoverable",
+		"BR/EDR Not Supported",
+		"LE and BR/EDR Controller",
+		"LE and BR/EDR Host",
+	}
+
+	// appearanceCategories matches the appearance categories to their names.
+	appearanceCategories = map[uint16]string{
+		0x00: "Unknown",
+		0x01: "Phone",
+		0x02: "Computer",
+		0x03: "Watch",
+		0x04: "Clock",
+		0x05: "Display",
+		0x06: "Remote Control",
+		0x07: "Eye-glasses",
+		0x08: "Tag",
+		0x09: "Keyring",
+		0x0a: "Media Player",
+		0x0b: "Barcode Scanner",
+		0x0c: "Thermometer",
+		0x0d: "Heart Rate Sensor",
+		0x0e: "Blood Pressure",
+		0x0f: "Human Interface Device",
+		0x10: "Glucose Meter",
+		0x11: "Running Walking Sensor",
+		0x12: "Cycling",
+		0x15: "Control Device",
+		0x16: "Network Device",
+		0x17: "Sensor",
+		0x18: "Light Fixtures",
+		0x19: "Fan",
+		0x1a: "HVAC",
+		0x1f: "Power Device",
+		0x20: "Light Source",
+		0x21: "Window Covering",
+		0x22: "Audio Sink",
+		0x23: "Audio Source",
+		0x29: "Hearing Aid",
+		0x31: "Pulse Oximeter",
+		0x32: "Weight Scale",
+		0x33: "Personal Mobility Device",
+		0x36: "Insulin Pump",
+		0x37: "Medication Delivery",
+		0x51: "Outdoor Sports Activity",
+	}
+
+	// eddystoneURLSchemes holds the URL scheme prefixes of Eddystone-URL frames.
+	eddystoneURLSchemes = []string{"http://www.", "https://www.", "http://", "https://"}
+
+	// eddystoneURLExpansions holds the URL expansion codes of Eddystone-URL frames.
+	eddystoneURLExpansions = []string{
+		".com/", ".org/", ".edu/", ".net/", ".info/", ".biz/", ".gov/",
+		".com", ".org", ".edu", ".net", ".info", ".biz", ".gov",
+	}
+)
+
+// decodeAdvertisement decodes the advertising data properties of a device.
+// Only the properties present in values are updated.
+func decodeAdvertisement(values map[string]dbus.Variant, advertisement *Advertisement) {
+	if v, ok := values["ManufacturerData"]; ok {
+		var data map[uint16]dbus.Variant
+
+		if v.Store(&data) == nil {
+			advertisement.ManufacturerData = make(map[uint16][]byte, len(data))
+
+			for id, value := range data {
+				var b []byte
+				if value.Store(&b) == nil {
+					advertisement.ManufacturerData[id] = b
+				}
+			}
+		}
+	}
+
+	if v, ok := values["ServiceData"]; ok {
+		var data map[string]dbus.Variant
+
+		if v.Store(&data) == nil {
+			advertisement.ServiceData = make(map[string][]byte, len(data))
+
+			for serviceUUID, value := range data {
+				var b []byte
+				if value.Store(&b) == nil {
+					advertisement.ServiceData[strings.ToLower(serviceUUID)] = b
+				}
+			}
+		}
+	}
+
+	if v, ok := values["AdvertisingFlags"]; ok {
+		v.Store(&advertisement.Flags)
+	}
+
+	if v, ok := values["TxPower"]; ok {
+		advertisement.HasTxPower = v.Store(&advertisement.TxPower) == nil
+	}
+
+	if v, ok := values["Appearance"]; ok {
+		advertisement.HasAppearance = v.Store(&advertisement.Appearance) == nil
+	}
+}
+
+// Fields returns the raw and decoded fields of the advertising data.
+func (a Advertisement) Fields() []AdvertisingField {
+	var fields []AdvertisingField
+
+	if a.Flags != nil {
+		fields = append(fields, AdvertisingField{
+			Type:    "Flags",
+			Raw:     hex.EncodeToString(a.Flags),
+			Decoded: decodeAdvertisingFlags(a.Flags),
+		})
+	}
+
+	if a.HasTxPower {
+		fields = append(fields, AdvertisingField{
+			Type:    "TxPower",
+			Raw:     fmt.Sprintf("%d", a.TxPower),
+			Decoded: fmt.Sprintf("%d dBm", a.TxPower),
+		})
+	}
+
+	if a.HasAppearance {
+		fields = append(fields, AdvertisingField{
+			Type:    "Appearance",
+			Raw:     fmt.Sprintf("0x%04x", a.Appearance),
+			Decoded: AppearanceName(a.Appearance),
+		})
+	}
+
+	ids := make([]uint16, 0, len(a.ManufacturerData))
+	for id := range a.ManufacturerData {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	for _, id := range ids {
+		data := a.ManufacturerData[id]
+
+		fields = append(fields, AdvertisingField{
+			Type:    "Manufacturer",
+			Name:    CompanyName(uint64(id)),
+			Raw:     hex.EncodeToString(data),
+			Decoded: decodeManufacturerData(id, data),
+		})
+	}
+
+	serviceUUIDs := make([]string, 0, len(a.ServiceData))
+	for serviceUUID := range a.ServiceData {
+		serviceUUIDs = append(serviceUUIDs, serviceUUID)
+	}
+	sort.Strings(serviceUUIDs)
+
+	for _, serviceUUID := range serviceUUIDs {
+		data := a.ServiceData[serviceUUID]
+
+		fields = append(fields, AdvertisingField{
+			Type:    "Service",
+			Name:    GattName(serviceUUID),
+			Raw:     hex.EncodeToString(data),
+			Decoded: decodeServiceData(serviceUUID, data),
+		})
+	}
+
+	return fields
+}
+
+// AppearanceName returns the category name of the appearance value.
+func AppearanceName(appearance uint16) string {
+	category, ok := appearanceCategories[appearance>>6]
+	if !ok {
+		return fmt.Sprintf("0x%04x", appearance)
+	}
+
+	if subcategory := appearance & 0x3f; subcategory != 0 {
+		return fmt.Sprintf("%s (subcategory %d)", category, subcategory)
+	}
+
+	return category
+}
+
+// decodeAdvertisingFlags decodes the advertising flags.
+func decodeAdvertisingFlags(flags []byte) string {
+	if len(flags) == 0 {
+		return ""
+	}
+
+	var decoded []string
+	for bit, flag := range advertisingFlags {
+		if flags[0]&(1<<bit) != 0 {
+			decoded = append(decoded, flag)
+		}
+	}
+
+	return strings.Join(decoded, ", ")
+}
+
+// decodeManufacturerData decodes the manufacturer data. Currently,
+// only iBeacon advertisements are decoded.
+func decodeManufacturerData(id uint16, data []byte) string {
+	if id != 0x004c || len(data) < 23 || data[0] != 0x02 || data[1] != 0x15 {
+		return ""
+	}
+
+	beaconUUID, err := uuid.FromBytes(data[2:18])
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"iBeacon UUID %s, Major %d, Minor %d, Measured Power %d dBm",
+		beaconUUID,
+		binary.BigEndian.Uint16(data[18:20]),
+		binary.BigEndian.Uint16(data[20:22]),
+		int8(data[22]),
+	)
+}
+
+// decodeServiceData decodes the service data. Currently, only
+// Eddystone UID, URL and TLM frames are decoded.
+func decodeServiceData(serviceUUID string, data []byte) string {
+	parsedUUID, err := uuid.Parse(serviceUUID)
+	if err != nil || parsedUUID.ID() != eddystoneService || len(data) < 2 {
+		return ""
+	}
+
+	switch data[0] {
+	case 0x00:
+		if len(data) < 18 {
+			return ""
+		}
+
+		return fmt.Sprintf(
+			"Eddystone-UID Namespace %x, Instance %x, TxPower %d dBm",
+			data[2:12], data[12:18], int8(data[1]),
+		)
+
+	case 0x10:
+		if len(data) < 3 || int(data[2]) >= len(eddystoneURLSchemes) {
+			return ""
+		}
+
+		url := eddystoneURLSchemes[data[2]]
+		for _, c := range data[3:] {
+			if int(c) < len(eddystoneURLExpansions) {
+				url += eddystoneURLExpansions[c]
+				continue
+			}
+
+			url += string(rune(c))
+		}
+
+		return fmt.Sprintf("Eddystone-URL %s, TxPower %d dBm", url, int8(data[1]))
+
+	case 0x20:
+		if len(data) < 14 || data[1] != 0x00 {
+			return ""
+		}
+
+		return fmt.Sprintf(
+			"Eddystone-TLM Battery %d mV, Temperature %.2f C, Advertisements %d, Uptime %ds",
+			binary.BigEndian.Uint16(data[2:4]),
+			float64(int16(binary.BigEndian.Uint16(data[4:6])))/256,
+			binary.BigEndian.Uint32(data[6:10]),
+			binary.BigEndian.Uint32(data[10:14])/10,
+		)
+
+	case 0x30:
+		if len(data) < 10 {
+			return ""
+		}
+
+		return fmt.Sprintf("Eddystone-EID %x, TxPower %d dBm", data[2:10], int8(data[1]))
+	}
+
+	return ""
+}
@@ -0,0 +1,319 @@
+package bluez
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	dbusBluezAdvertisingManagerIface = "org.bluez.LEAdvertisingManager1"
+	dbusBluezAdvertisementIface      = "org.bluez.LEAdvertisement1"
+
+	dbusIntrospectableIface = "org.freedesktop.DBus.Introspectable"
+	dbusPropertiesIface     = "org.freedesktop.DBus.Properties"
+
+	advertisementBasePath = "/org/bluez/bluetuith/advertisement/"
+)
+
+// AdvertisementOptions holds the data to be advertised from an adapter.
+type AdvertisementOptions struct {
+	Type             string
+	LocalName        string
+	ServiceUUIDs     []string
+	ManufacturerData map[uint16][]byte
+	ServiceData      map[string][]byte
+	IncludeTxPower   bool
+	Timeout          uint16
+}
+
+// AdvertisingInstances holds the number of active and supported
+// advertising instances of an adapter.
+type AdvertisingInstances struct {
+	Active    byte
+	Supported byte
+}
+
+// advertiser holds an exported advertisement of an adapter.
+type advertiser struct {
+	adapterPath string
+
+	path  dbus.ObjectPath
+	bluez *Bluez
+}
+
+// The advertisement types.
+const (
+	AdvertisementPeripheral = "peripheral"
+	AdvertisementBroadcast  = "broadcast"
+)
+
+// eddystoneURLPrefixes holds the URL scheme prefixes of Eddystone-URL frames,
+// ordered so that the longest matching prefix is used.
+var eddystoneURLPrefixes = []byte{1, 0, 3, 2}
+
+// StartAdvertising exports an advertisement with the provided options,
+// and registers it with the adapter. Any existing advertisement of the
+// adapter is stopped first.
+func (b *Bluez) StartAdvertising(adapterPath string, options AdvertisementOptions) error {
+	if err := b.StopAdvertising(adapterPath); err != nil {
+		return err
+	}
+
+	path := dbus.ObjectPath(advertisementBasePath + GetAdapterID(adapterPath))
+	adv := &advertiser{adapterPath: adapterPath, path: path, bluez: b}
+
+	if err := adv.export(options); err != nil {
+		adv.unexport()
+		return err
+	}
+
+	// The advertisement is stored before it is registered, so that
+	// a release which arrives during registration can remove it.
+	b.advertisingLock.Lock()
+	b.advertisers[adapterPath] = adv
+	b.advertisingLock.Unlock()
+
+	err := b.conn.Object(dbusBluezName, dbus.ObjectPath(adapterPath)).
+		Call(dbusBluezAdvertisingManagerIface+".RegisterAdvertisement", 0, path, map[string]dbus.Variant{}).
+		Store()
+	if err != nil {
+		b.advertisingLock.Lock()
+		if b.advertisers[adapterPath] == adv {
+			delete(b.advertisers, adapterPath)
+		}
+		b.advertisingLock.Unlock()
+
+		adv.unexport()
+		return err
+	}
+
+	if !b.IsAdvertising(adapterPath) {
+		return errors.New("The advertisement was released")
+	}
+
+	return nil
+}
+
+// StopAdvertising unregisters and removes the advertisement of the adapter.
+func (b *Bluez) StopAdvertising(adapterPath string) error {
+	b.advertisingLock.Lock()
+	adv, ok := b.advertisers[adapterPath]
+	delete(b.advertisers, adapterPath)
+	b.advertisingLock.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	defer adv.unexport()
+
+	return b.conn.Object(dbusBluezName, dbus.ObjectPath(adapterPath)).
+		Call(dbusBluezAdvertisingManagerIface+".UnregisterAdvertisement", 0, adv.path).
+		Store()
+}
+
+// IsAdvertising returns whether an advertisement is registered with the adapter.
+func (b *Bluez) IsAdvertising(adapterPath string) bool {
+	b.advertisingLock.Lock()
+	defer b.advertisingLock.Unlock()
+
+	_, ok := b.advertisers[adapterPath]
+
+	return ok
+}
+
+// SetAdvertisingReleaseHandler sets the handler which is called with the
+// adapter path, when BlueZ removes an advertisement of the adapter.
+func (b *Bluez) SetAdvertisingReleaseHandler(handler func(adapterPath string)) {
+	b.advertisingLock.Lock()
+	defer b.advertisingLock.Unlock()
+
+	b.advertisingRelease = handler
+}
+
+// GetAdvertisingInstances returns the number of active and
+// supported advertising instances of the adapter.
+func (b *Bluez) GetAdvertisingInstances(adapterPath string) (AdvertisingInstances, error) {
+	var instances AdvertisingInstances
+
+	props := make(map[string]dbus.Variant)
+	if err := b.conn.Object(dbusBluezName, dbus.ObjectPath(adapterPath)).
+		Call(dbusPropertiesGetAllPath, 0, dbusBluezAdvertisingManagerIface).
+		Store(&props); err != nil {
+		return instances, err
+	}
+
+	props["ActiveInstances"].Store(&instances.Active)
+	props["SupportedInstances"].Store(&instances.Supported)
+
+	return instances, nil
+}
+
+// IBeaconAdvertisement returns the advertisement options for an iBeacon.
+func IBeaconAdvertisement(beaconUUID string, major, minor uint16, measuredPower int8) (AdvertisementOptions, error) {
+	parsedUUID, err := uuid.Parse(beaconUUID)
+	if err != nil {
+		return AdvertisementOptions{}, errors.Wrap(err, "Invalid iBeacon UUID")
+	}
+
+	data := []byte{0x02, 0x15}
+	data = append(data, parsedUUID[:]...)
+	data = append(data, make([]byte, 5)...)
+
+	binary.BigEndian.PutUint16(data[18:20], major)
+	binary.BigEndian.PutUint16(data[20:22], minor)
+	data[22] = byte(measuredPower)
+
+	return AdvertisementOptions{
+		Type:             AdvertisementBroadcast,
+		ManufacturerData: map[uint16][]byte{0x004c: data},
+	}, nil
+}
+
+// EddystoneURLAdvertisement returns the advertisement options for an Eddystone-URL beacon.
+func EddystoneURLAdvertisement(url string, txPower int8) (AdvertisementOptions, error) {
+	data := []byte{0x10, byte(txPower)}
+
+	scheme := -1
+	for _, prefix := range eddystoneURLPrefixes {
+		if strings.HasPrefix(url, eddystoneURLSchemes[prefix]) {
+			scheme = int(prefix)
+			url = strings.TrimPrefix(url, eddystoneURLSchemes[prefix])
+
+			break
+		}
+	}
+	if scheme < 0 {
+		return AdvertisementOptions{}, fmt.Errorf("Eddystone URL must start with http:// or https://")
+	}
+	data = append(data, byte(scheme))
+
+EncodeURL:
+	for len(url) > 0 {
+		for code, expansion := range eddystoneURLExpansions {
+			if strings.HasPrefix(url, expansion) {
+				data = append(data, byte(code))
+				url = url[len(expansion):]
+
+				continue EncodeURL
+			}
+		}
+
+		data = append(data, url[0])
+		url = url[1:]
+	}
+
+	if len(data) > 20 {
+		return AdvertisementOptions{}, fmt.Errorf("Eddystone URL is too long")
+	}
+
+	eddystoneUUID := serviceUUID(eddystoneService)
+
+	return AdvertisementOptions{
+		Type:         AdvertisementBroadcast,
+		ServiceUUIDs: []string{eddystoneUUID},
+		ServiceData:  map[string][]byte{eddystoneUUID: data},
+	}, nil
+}
+
+// export exports the advertisement object with the provided options.
+func (a *advertiser) export(options AdvertisementOptions) error {
+	if options.Type == "" {
+		options.Type = AdvertisementPeripheral
+	}
+
+	properties := map[string]*prop.Prop{
+		"Type": {Value: options.Type},
+	}
+	if options.LocalName != "" {
+		properties["LocalName"] = &prop.Prop{Value: options.LocalName}
+	}
+	if options.ServiceUUIDs != nil {
+		properties["ServiceUUIDs"] = &prop.Prop{Value: options.ServiceUUIDs}
+	}
+	if options.ManufacturerData != nil {
+		data := make(map[uint16]dbus.Variant, len(options.ManufacturerData))
+		for id, value := range options.ManufacturerData {
+			data[id] = dbus.MakeVariant(value)
+		}
+
+		properties["ManufacturerData"] = &prop.Prop{Value: data}
+	}
+	if options.ServiceData != nil {
+		data := make(map[string]dbus.Variant, len(options.ServiceData))
+		for serviceUUID, value := range options.ServiceData {
+			data[serviceUUID] = dbus.MakeVariant(value)
+		}
+
+		properties["ServiceData"] = &prop.Prop{Value: data}
+	}
+	if options.IncludeTxPower {
+		properties["Includes"] = &prop.Prop{Value: []string{"tx-power"}}
+	}
+	if options.Timeout > 0 {
+		properties["Timeout"] = &prop.Prop{Value: options.Timeout}
+	}
+
+	if err := a.bluez.conn.Export(a, a.path, dbusBluezAdvertisementIface); err != nil {
+		return err
+	}
+
+	props, err := prop.Export(a.bluez.conn, a.path, prop.Map{dbusBluezAdvertisementIface: properties})
+	if err != nil {
+		return err
+	}
+
+	node := &introspect.Node{
+		Name: string(a.path),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+			{
+				Name:       dbusBluezAdvertisementIface,
+				Methods:    introspect.Methods(a),
+				Properties: props.Introspection(dbusBluezAdvertisementIface),
+			},
+		},
+	}
+
+	return a.bluez.conn.Export(introspect.NewIntrospectable(node), a.path, dbusIntrospectableIface)
+}
+
+// unexport removes the exported advertisement object.
+func (a *advertiser) unexport() {
+	for _, iface := range []string{
+		dbusBluezAdvertisementIface,
+		dbusPropertiesIface,
+		dbusIntrospectableIface,
+	} {
+		a.bluez.conn.Export(nil, a.path, iface)
+	}
+}
+
+// Release is called by BlueZ when the advertisement is removed.
+func (a *advertiser) Release() *dbus.Error {
+	b := a.bluez
+
+	b.advertisingLock.Lock()
+	defer b.advertisingLock.Unlock()
+
+	if b.advertisers[a.adapterPath] != a {
+		return nil
+	}
+	delete(b.advertisers, a.adapterPath)
+
+	go a.unexport()
+
+	if b.advertisingRelease != nil {
+		go b.advertisingRelease(a.adapterPath)
+	}
+
+	return nil
+}
@@ -0,0 +1,385 @@
+package bluez
+
+import (
+	"github.com/jfreymuth/pulse/proto"
+	"github.com/pkg/errors"
+)
+
+// AudioEventType describes the type of object a pulseaudio event refers to.
+type AudioEventType int
+
+// The different types of pulseaudio events.
+const (
+	AudioEventSink AudioEventType = iota
+	AudioEventSource
+	AudioEventSinkInput
+	AudioEventSourceOutput
+	AudioEventCard
+)
+
+// AudioEvent holds a pulseaudio event.
+type AudioEvent struct {
+	Type    AudioEventType
+	Index   uint32
+	Added   bool
+	Removed bool
+}
+
+// AudioStream stores information about a capture stream.
+type AudioStream struct {
+	Index       uint32
+	SourceIndex uint32
+	Application string
+}
+
+// AudioWatcher holds a pulseaudio connection which listens for events.
+type AudioWatcher struct {
+	Events chan AudioEvent
+
+	done   chan struct{}
+	client *pulseClient
+}
+
+// volumeStep is the volume step in percent.
+const volumeStep = 5
+
+// volumeControlApps lists the applications which monitor sources
+// only to display their volume levels.
+var volumeControlApps = []string{
+	"org.PulseAudio.pavucontrol",
+	"org.gnome.VolumeControl",
+	"org.kde.kmixd",
+	"org.kde.plasma-pa",
+}
+
+// WatchAudio subscribes to pulseaudio sink, source, stream and card events.
+// The Events channel is closed when the connection to the server is lost.
+func WatchAudio() (*AudioWatcher, error) {
+	client, err := newPulseClient()
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &AudioWatcher{
+		Events: make(chan AudioEvent, 100),
+		done:   make(chan struct{}),
+		client: client,
+	}
+
+	eventTypes := map[proto.SubscriptionEventType]AudioEventType{
+		proto.EventSink:             AudioEventSink,
+		proto.EventSource:           AudioEventSource,
+		proto.EventSinkSinkInput:    AudioEventSinkInput,
+		proto.EventSinkSourceOutput: AudioEventSourceOutput,
+		proto.EventCard:             AudioEventCard,
+	}
+
+	client.Callback = func(msg interface{}) {
+		switch msg := msg.(type) {
+		case *proto.SubscribeEvent:
+			eventType, ok := eventTypes[msg.Event.GetFacility()]
+			if !ok {
+				return
+			}
+
+			select {
+			case watcher.Events <- AudioEvent{
+				Type:    eventType,
+				Index:   msg.Index,
+				Added:   msg.Event.GetType() == proto.EventNew,
+				Removed: msg.Event.GetType() == proto.EventRemove,
+			}:
+
+			case <-watcher.done:
+			}
+
+		case *proto.ConnectionClosed:
+			close(watcher.Events)
+		}
+	}
+
+	err = client.Request(&proto.Subscribe{
+		Mask: proto.SubscriptionMaskSink |
+			proto.SubscriptionMaskSource |
+			proto.SubscriptionMaskSinkInput |
+			proto.SubscriptionMaskSourceInput |
+			proto.SubscriptionMaskCard,
+	}, nil)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return watcher, nil
+}
+
+// Close closes the watcher's connection to the pulseaudio server.
+func (w *AudioWatcher) Close() {
+	close(w.done)
+	w.client.Close()
+}
+
+// ListDeviceSinks returns the first sink of each Bluetooth sound card,
+// mapped to the address of the device.
+func ListDeviceSinks() (map[string]AudioEndpoint, error) {
+	var cards proto.GetCardInfoListReply
+
+	client, err := newPulseClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if err := client.Request(&proto.GetCardInfoList{}, &cards); err != nil {
+		return nil, err
+	}
+
+	deviceSinks := make(map[string]AudioEndpoint)
+
+	for _, card := range cards {
+		address := cardAddress(card)
+		if address == "" {
+			continue
+		}
+
+		sinks, err := client.getSinks(card.CardIndex)
+		if err != nil {
+			return nil, err
+		}
+		if sinks == nil {
+			continue
+		}
+
+		deviceSinks[address] = sinks[0]
+	}
+
+	return deviceSinks, nil
+}
+
+// ListCaptureStreams lists all capture streams, excluding those which record
+// from monitor sources and those which belong to volume control applications.
+func ListCaptureStreams() ([]AudioStream, error) {
+	var streams []AudioStream
+	var sources proto.GetSourceInfoListReply
+	var outputs proto.GetSourceOutputInfoListReply
+
+	client, err := newPulseClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if err := client.Request(&proto.GetSourceInfoList{}, &sources); err != nil {
+		return nil, err
+	}
+
+	if err := client.Request(&proto.GetSourceOutputInfoList{}, &outputs); err != nil {
+		return nil, err
+	}
+
+	monitors := make(map[uint32]struct{})
+	for _, source := range sources {
+		if source.MonitorSourceIndex != proto.Undefined {
+			monitors[source.SourceIndex] = struct{}{}
+		}
+	}
+
+OutputLoop:
+	for _, output := range outputs {
+		var application string
+
+		if _, ok := monitors[output.SourceIndex]; ok {
+			continue
+		}
+
+		if id, ok := output.Properties["application.id"]; ok {
+			application = id.String()
+		}
+
+		for _, app := range volumeControlApps {
+			if application == app {
+				continue OutputLoop
+			}
+		}
+
+		streams = append(streams, AudioStream{
+			Index:       output.SourceOutpuIndex,
+			SourceIndex: output.SourceIndex,
+			Application: application,
+		})
+	}
+
+	return streams, nil
+}
+
+// GetCaptureStream returns the capture stream with the provided index.
+func GetCaptureStream(streamIndex uint32) (AudioStream, error) {
+	streams, err := ListCaptureStreams()
+	if err != nil {
+		return AudioStream{}, err
+	}
+
+	for _, stream := range streams {
+		if stream.Index == streamIndex {
+			return stream, nil
+		}
+	}
+
+	return AudioStream{}, errors.New("Capture stream not found")
+}
+
+// IsCaptureSource returns whether the source is the default source,
+// or a source of the device's sound card.
+func IsCaptureSource(deviceAddress string, sourceIndex uint32) bool {
+	var info proto.GetServerInfoReply
+	var source proto.GetSourceInfoReply
+
+	client, err := newPulseClient()
+	if err != nil {
+		return false
+	}
+	defer client.Close()
+
+	if err := client.Request(&proto.GetServerInfo{}, &info); err != nil {
+		return false
+	}
+
+	if err := client.Request(&proto.GetSourceInfo{SourceIndex: sourceIndex}, &source); err != nil {
+		return false
+	}
+
+	if source.SourceName == info.DefaultSourceName {
+		return true
+	}
+
+	card, err := client.getCard(deviceAddress)
+	if err != nil {
+		return false
+	}
+
+	return source.CardIndex == card.CardIndex
+}
+
+// IsDefaultSink returns whether a sink of the device's sound card
+// is the default sink.
+func IsDefaultSink(deviceAddress string) bool {
+	var info proto.GetServerInfoReply
+
+	client, err := newPulseClient()
+	if err != nil {
+		return false
+	}
+	defer client.Close()
+
+	card, err := client.getCard(deviceAddress)
+	if err != nil {
+		return false
+	}
+
+	sinks, err := client.getSinks(card.CardIndex)
+	if err != nil {
+		return false
+	}
+
+	if err := client.Request(&proto.GetServerInfo{}, &info); err != nil {
+		return false
+	}
+
+	for _, sink := range sinks {
+		if sink.Name == info.DefaultSinkName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChangeVolume raises or lowers the volume of the sink by a single step.
+func (a AudioEndpoint) ChangeVolume(raise bool) error {
+	client, err := newPulseClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	percent := a.VolumePercent()
+	if raise {
+		percent += volumeStep
+	} else {
+		percent -= volumeStep
+	}
+
+	switch {
+	case percent > 100:
+		percent = 100
+
+	case percent < 0:
+		percent = 0
+	}
+
+	volumes := make(proto.ChannelVolumes, len(a.Volume))
+	for i := range volumes {
+		volumes[i] = uint32(percent * int(proto.VolumeNorm) / 100)
+	}
+
+	err = client.Request(&proto.SetSinkVolume{
+		SinkIndex:      a.Index,
+		ChannelVolumes: volumes,
+	}, nil)
+	if err != nil {
+		return errors.Wrap(err, "Cannot set the volume")
+	}
+
+	return nil
+}
+
+// ToggleMute toggles the mute state of the sink.
+func (a AudioEndpoint) ToggleMute() error {
+	client, err := newPulseClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.Request(&proto.SetSinkMute{
+		SinkIndex: a.Index,
+		Mute:      !a.Mute,
+	}, nil)
+	if err != nil {
+		return errors.Wrap(err, "Cannot set the mute state")
+	}
+
+	return nil
+}
+
+// VolumePercent returns the average volume of the endpoint in percent.
+func (a AudioEndpoint) VolumePercent() int {
+	var total int
+
+	if len(a.Volume) == 0 {
+		return 0
+	}
+
+	for _, volume := range a.Volume {
+		total += int(volume)
+	}
+
+	norm := int(proto.VolumeNorm)
+
+	return (total/len(a.Volume)*100 + norm/2) / norm
+}
+
+// cardAddress returns the Bluetooth address of the sound card.
+func cardAddress(card *proto.GetCardInfoReply) string {
+	if bus, ok := card.Properties["device.bus"]; !ok || bus.String() != "bluetooth" {
+		return ""
+	}
+
+	for _, prop := range []string{"device.string", "api.bluez5.address"} {
+		if addr, ok := card.Properties[prop]; ok {
+			return addr.String()
+		}
+	}
+
+	return ""
+}
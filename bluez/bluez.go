@@ -37,6 +37,10 @@ type Bluez struct {
 
 	CurrentPlayer dbus.ObjectPath
 	PlayerLock    sync.Mutex
+
+	advertisers        map[string]*advertiser
+	advertisingRelease func(adapterPath string)
+	advertisingLock    sync.Mutex
 }
 
 // NewBluez returns a new Bluez.
@@ -49,8 +53,9 @@ func NewBluez() (*Bluez, error) {
 	}
 
 	b = &Bluez{
-		conn:  conn,
-		Store: make(map[string]StoreObject),
+		conn:        conn,
+		Store:       make(map[string]StoreObject),
+		advertisers: make(map[string]*advertiser),
 	}
 	if err := b.RefreshStore(); err != nil {
 		return nil, errors.Wrapf(err, "unable to populate cache")
@@ -187,6 +192,7 @@ func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 			if err := DecodeVariantMap(objMap, &device); err != nil {
 				return nil
 			}
+			decodeAdvertisement(objMap, &device.Advertisement)
 
 			b.addDeviceToStore(device)
 
@@ -211,6 +217,16 @@ func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 			}
 
 			return device
+
+		case dbusBluezGattCharacteristicIface:
+			var value []byte
+
+			v, ok := objMap["Value"]
+			if !ok || v.Store(&value) != nil {
+				return nil
+			}
+
+			return GattValue{Path: string(signal.Path), Value: value}
 		}
 
 	case "org.freedesktop.DBus.ObjectManager.InterfacesAdded":
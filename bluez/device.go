@@ -16,7 +16,10 @@
 package bluez
 
 import (
+	"fmt"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/godbus/dbus/v5"
 )
@@ -46,6 +49,8 @@ type Device struct {
 	RSSI          int16
 	Class         uint32
 	Percentage    int
+
+	Advertisement Advertisement `codec:"-"`
 }
 
 // HaveService checks if the device has the specified service.
@@ -94,27 +99,38 @@ func (b *Bluez) GetDevice(devicePath string) Device {
 	return b.getDeviceFromStore(devicePath)
 }
 
-// GetDevices gets the stored devices.
+// GetDevices gets the stored devices of the current adapter.
 func (b *Bluez) GetDevices() []Device {
+	return b.GetAdapterDevices(b.GetCurrentAdapter().Path)
+}
+
+// GetAdapterDevices gets the stored devices of the provided adapter.
+func (b *Bluez) GetAdapterDevices(adapterPath string) []Device {
 	b.StoreLock.Lock()
 	defer b.StoreLock.Unlock()
 
 	var devices []Device
 
-	store, ok := b.Store[b.GetCurrentAdapter().Path]
+	store, ok := b.Store[adapterPath]
 	if !ok {
 		return nil
 	}
 
 	for _, device := range store.Devices {
-		if device.Paired || device.Trusted || device.Blocked {
-			devices = append([]Device{device}, devices...)
-			continue
-		}
-
 		devices = append(devices, device)
 	}
 
+	sort.Slice(devices, func(i, j int) bool {
+		iKnown := devices[i].Paired || devices[i].Trusted || devices[i].Blocked
+		jKnown := devices[j].Paired || devices[j].Trusted || devices[j].Blocked
+
+		if iKnown != jKnown {
+			return iKnown
+		}
+
+		return devices[i].Address < devices[j].Address
+	})
+
 	return devices
 }
 
@@ -146,6 +162,7 @@ func (b *Bluez) ConvertToDevice(path string, values map[string]dbus.Variant, dev
 
 	device.Path = path
 	device.Type = GetDeviceType(device.Class)
+	decodeAdvertisement(values, &device.Advertisement)
 	if p, err := b.GetBatteryPercentage(path); err == nil {
 		device.Percentage = int(p)
 	}
@@ -247,6 +264,78 @@ func GetDeviceType(class uint32) string {
 	return "Unknown"
 }
 
+// The major device classes, and the minor device classes of the
+// computer, phone and audio/video major device classes.
+var (
+	majorDeviceClasses = map[uint32]string{
+		0x00: "Miscellaneous",
+		0x01: "Computer",
+		0x02: "Phone",
+		0x03: "Network Access Point",
+		0x04: "Audio/Video",
+		0x05: "Peripheral",
+		0x06: "Imaging",
+		0x07: "Wearable",
+		0x08: "Toy",
+		0x09: "Health",
+		0x1f: "Uncategorized",
+	}
+
+	minorDeviceClasses = map[uint32]map[uint32]string{
+		0x01: {
+			0x01: "Desktop",
+			0x02: "Server",
+			0x03: "Laptop",
+			0x04: "Handheld",
+			0x05: "Palm-size",
+			0x06: "Wearable",
+			0x07: "Tablet",
+		},
+		0x02: {
+			0x01: "Cellular",
+			0x02: "Cordless",
+			0x03: "Smartphone",
+			0x04: "Modem",
+			0x05: "ISDN",
+		},
+		0x04: {
+			0x01: "Headset",
+			0x02: "Hands-free",
+			0x04: "Microphone",
+			0x05: "Loudspeaker",
+			0x06: "Headphones",
+			0x07: "Portable Audio",
+			0x08: "Car Audio",
+			0x09: "Set-top Box",
+			0x0a: "HiFi Audio",
+			0x0b: "VCR",
+			0x0c: "Video Camera",
+			0x0d: "Camcorder",
+			0x0e: "Video Monitor",
+			0x0f: "Video Display and Loudspeaker",
+			0x10: "Video Conferencing",
+			0x12: "Gaming/Toy",
+		},
+	}
+)
+
+// FormatDeviceClass returns the class of device in hexadecimal,
+// along with its decoded major and minor device classes.
+func FormatDeviceClass(class uint32) string {
+	major := (class & 0x1f00) >> 8
+	minor := (class & 0xfc) >> 2
+
+	classes := []string{"Unknown"}
+	if name, ok := majorDeviceClasses[major]; ok {
+		classes[0] = name
+	}
+	if name, ok := minorDeviceClasses[major][minor]; ok {
+		classes = append(classes, name)
+	}
+
+	return fmt.Sprintf("0x%06x (%s)", class, strings.Join(classes, ", "))
+}
+
 // GetDeviceProperties gathers all the properties for a bluetooth device.
 func (b *Bluez) GetDeviceProperties(devicePath string) (map[string]dbus.Variant, error) {
 	result := make(map[string]dbus.Variant)
@@ -0,0 +1,228 @@
+package bluez
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+)
+
+const (
+	dbusBluezMediaTransportIface = "org.bluez.MediaTransport1"
+	dbusBluezNetworkIface        = "org.bluez.Network1"
+)
+
+// DeviceProfile holds a profile which is supported by a device.
+// Connected is valid only if State is true, since BlueZ does not
+// report the connection state of every profile.
+type DeviceProfile struct {
+	UUID      string
+	Name      string
+	Connected bool
+	State     bool
+}
+
+// avrcpServices holds the service classes of the AVRCP profile,
+// whose connection state is reported by the MediaControl1 interface.
+var avrcpServices = []uint32{0x110c, 0x110e, 0x110f}
+
+// transportRoles matches the service class of a media transport, which
+// is the role of the local endpoint, to the service classes of the
+// counterpart roles which are advertised by the remote device.
+var transportRoles = map[uint32][]uint32{
+	AUDIO_SOURCE_SVCLASS_ID:  {AUDIO_SINK_SVCLASS_ID},
+	AUDIO_SINK_SVCLASS_ID:    {AUDIO_SOURCE_SVCLASS_ID},
+	HANDSFREE_AGW_SVCLASS_ID: {HANDSFREE_SVCLASS_ID},
+	HANDSFREE_SVCLASS_ID:     {HANDSFREE_AGW_SVCLASS_ID},
+	HEADSET_AGW_SVCLASS_ID:   {HEADSET_SVCLASS_ID, HEADSET_HS_SVCLASS_ID},
+	HEADSET_SVCLASS_ID:       {HEADSET_AGW_SVCLASS_ID},
+	HEADSET_HS_SVCLASS_ID:    {HEADSET_AGW_SVCLASS_ID},
+}
+
+// ConnectProfile connects the profile with the provided UUID of the device.
+func (b *Bluez) ConnectProfile(devicePath, uuid string) error {
+	return b.CallDevice(devicePath, "ConnectProfile", 0, uuid).Store()
+}
+
+// DisconnectProfile disconnects the profile with the provided UUID of the device.
+func (b *Bluez) DisconnectProfile(devicePath, uuid string) error {
+	return b.CallDevice(devicePath, "DisconnectProfile", 0, uuid).Store()
+}
+
+// GetDeviceProfiles returns the profiles supported by the device, along with
+// their connection states wherever BlueZ reveals them.
+func (b *Bluez) GetDeviceProfiles(device Device) ([]DeviceProfile, error) {
+	states, err := b.profileStates(device.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]DeviceProfile, 0, len(device.UUIDs))
+	for _, uuid := range device.UUIDs {
+		uuid = strings.ToLower(uuid)
+		connected, ok := states[uuid]
+
+		profiles = append(profiles, DeviceProfile{
+			UUID:      uuid,
+			Name:      ServiceType(uuid),
+			Connected: connected,
+			State:     ok,
+		})
+	}
+
+	return profiles, nil
+}
+
+// profileStates returns the connection states of the device's profiles,
+// mapped to the profile UUIDs. The states are determined from the media
+// transports, media control and network interfaces of the device.
+func (b *Bluez) profileStates(devicePath string) (map[string]bool, error) {
+	states := make(map[string]bool)
+
+	objects, err := b.ManagedObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	for path, object := range objects {
+		if transport, ok := object[dbusBluezMediaTransportIface]; ok {
+			var device dbus.ObjectPath
+			var uuid string
+
+			if transport["Device"].Store(&device) != nil || transport["UUID"].Store(&uuid) != nil {
+				continue
+			}
+			if string(device) == devicePath {
+				for _, remoteUUID := range transportRemoteUUIDs(uuid) {
+					states[remoteUUID] = true
+				}
+			}
+		}
+
+		if string(path) != devicePath {
+			continue
+		}
+
+		if control, ok := object[dbusBluezMediaControlIface]; ok {
+			var connected bool
+			if control["Connected"].Store(&connected) == nil {
+				for _, svclass := range avrcpServices {
+					states[serviceUUID(svclass)] = connected
+				}
+			}
+		}
+
+		if network, ok := object[dbusBluezNetworkIface]; ok {
+			var connected bool
+			var uuid string
+
+			if network["Connected"].Store(&connected) == nil && connected &&
+				network["UUID"].Store(&uuid) == nil {
+				states[strings.ToLower(uuid)] = true
+			}
+		}
+	}
+
+	return states, nil
+}
+
+// ProfileStateDevice returns the path of the device whose profile connection
+// states may have been changed by the signal. The states change when a media
+// transport is added or removed, or when the media control or network
+// interface of the device is connected or disconnected.
+func ProfileStateDevice(signal *dbus.Signal) (string, bool) {
+	var path dbus.ObjectPath
+	var ifaces []string
+
+	switch signal.Name {
+	case "org.freedesktop.DBus.Properties.PropertiesChanged":
+		if len(signal.Body) < 2 {
+			return "", false
+		}
+
+		iface, _ := signal.Body[0].(string)
+		changed, _ := signal.Body[1].(map[string]dbus.Variant)
+		if _, ok := changed["Connected"]; !ok ||
+			(iface != dbusBluezMediaControlIface && iface != dbusBluezNetworkIface) {
+			return "", false
+		}
+
+		return string(signal.Path), true
+
+	case "org.freedesktop.DBus.ObjectManager.InterfacesAdded":
+		if len(signal.Body) < 2 {
+			return "", false
+		}
+
+		path, _ = signal.Body[0].(dbus.ObjectPath)
+		objects, _ := signal.Body[1].(map[string]map[string]dbus.Variant)
+		for iface := range objects {
+			ifaces = append(ifaces, iface)
+		}
+
+	case "org.freedesktop.DBus.ObjectManager.InterfacesRemoved":
+		if len(signal.Body) < 2 {
+			return "", false
+		}
+
+		path, _ = signal.Body[0].(dbus.ObjectPath)
+		ifaces, _ = signal.Body[1].([]string)
+
+	default:
+		return "", false
+	}
+
+	for _, iface := range ifaces {
+		if iface != dbusBluezMediaTransportIface {
+			continue
+		}
+
+		// Media transports are placed under the path of their device,
+		// for example "/org/bluez/hci0/dev_XX_XX_XX_XX_XX_XX/sep1/fd0".
+		elements := strings.Split(string(path), "/")
+		for i, element := range elements {
+			if strings.HasPrefix(element, "dev_") {
+				return strings.Join(elements[:i+1], "/"), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// transportRemoteUUIDs returns the UUIDs of the remote device's profiles,
+// which correspond to the UUID of the media transport.
+func transportRemoteUUIDs(uuid string) []string {
+	uuid = strings.ToLower(uuid)
+
+	var svclass uint32
+	if _, err := fmt.Sscanf(uuid, "%08x-0000-1000-8000-00805f9b34fb", &svclass); err != nil {
+		return []string{uuid}
+	}
+
+	roles, ok := transportRoles[svclass]
+	if !ok {
+		return []string{uuid}
+	}
+
+	uuids := make([]string, 0, len(roles))
+	for _, role := range roles {
+		uuids = append(uuids, serviceUUID(role))
+	}
+
+	return uuids
+}
+
+// serviceUUID returns the full UUID of the service class.
+func serviceUUID(svclass uint32) string {
+	return fmt.Sprintf("%08x-0000-1000-8000-00805f9b34fb", svclass)
+}
+
+// IsAlreadyConnected returns whether the error was returned because
+// the device or profile is already connected.
+func IsAlreadyConnected(err error) bool {
+	var dbusErr dbus.Error
+
+	return errors.As(err, &dbusErr) && dbusErr.Name == "org.bluez.Error.AlreadyConnected"
+}
@@ -0,0 +1,253 @@
+package bluez
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	dbusBluezGattServiceIface        = "org.bluez.GattService1"
+	dbusBluezGattCharacteristicIface = "org.bluez.GattCharacteristic1"
+	dbusBluezGattDescriptorIface     = "org.bluez.GattDescriptor1"
+)
+
+// GattService holds a GATT service of a device,
+// along with its characteristics.
+type GattService struct {
+	Path    string
+	UUID    string
+	Primary bool
+
+	Characteristics []GattCharacteristic
+}
+
+// GattCharacteristic holds a GATT characteristic of a service,
+// along with its descriptors.
+type GattCharacteristic struct {
+	Path      string
+	UUID      string
+	Flags     []string
+	Notifying bool
+	Value     []byte
+
+	Descriptors []GattDescriptor
+}
+
+// GattDescriptor holds a GATT descriptor of a characteristic.
+type GattDescriptor struct {
+	Path  string
+	UUID  string
+	Flags []string
+	Value []byte
+}
+
+// GattValue holds the changed value of a GATT characteristic.
+type GattValue struct {
+	Path  string
+	Value []byte
+}
+
+// GetGattServices returns the GATT services of the device, along with
+// their characteristics and descriptors, sorted by their object paths.
+func (b *Bluez) GetGattServices(devicePath string) ([]GattService, error) {
+	objects, err := b.ManagedObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var services []GattService
+	characteristics := make(map[string][]GattCharacteristic)
+	descriptors := make(map[string][]GattDescriptor)
+
+	for path, object := range objects {
+		if !strings.HasPrefix(string(path), devicePath+"/") {
+			continue
+		}
+
+		if values, ok := object[dbusBluezGattServiceIface]; ok {
+			service := GattService{Path: string(path)}
+
+			values["UUID"].Store(&service.UUID)
+			values["Primary"].Store(&service.Primary)
+
+			services = append(services, service)
+		}
+
+		if values, ok := object[dbusBluezGattCharacteristicIface]; ok {
+			var service dbus.ObjectPath
+
+			characteristic := GattCharacteristic{Path: string(path)}
+
+			values["UUID"].Store(&characteristic.UUID)
+			values["Flags"].Store(&characteristic.Flags)
+			values["Notifying"].Store(&characteristic.Notifying)
+			values["Value"].Store(&characteristic.Value)
+			values["Service"].Store(&service)
+
+			characteristics[string(service)] = append(characteristics[string(service)], characteristic)
+		}
+
+		if values, ok := object[dbusBluezGattDescriptorIface]; ok {
+			var characteristic dbus.ObjectPath
+
+			descriptor := GattDescriptor{Path: string(path)}
+
+			values["UUID"].Store(&descriptor.UUID)
+			values["Flags"].Store(&descriptor.Flags)
+			values["Value"].Store(&descriptor.Value)
+			values["Characteristic"].Store(&characteristic)
+
+			descriptors[string(characteristic)] = append(descriptors[string(characteristic)], descriptor)
+		}
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Path < services[j].Path
+	})
+
+	for i, service := range services {
+		chars := characteristics[service.Path]
+		sort.Slice(chars, func(i, j int) bool {
+			return chars[i].Path < chars[j].Path
+		})
+
+		for c, characteristic := range chars {
+			descs := descriptors[characteristic.Path]
+			sort.Slice(descs, func(i, j int) bool {
+				return descs[i].Path < descs[j].Path
+			})
+
+			chars[c].Descriptors = descs
+		}
+
+		services[i].Characteristics = chars
+	}
+
+	return services, nil
+}
+
+// ReadCharacteristic reads the value of the GATT characteristic.
+func (b *Bluez) ReadCharacteristic(characteristicPath string) ([]byte, error) {
+	return b.readGattValue(characteristicPath, dbusBluezGattCharacteristicIface)
+}
+
+// WriteCharacteristic writes the value to the GATT characteristic.
+func (b *Bluez) WriteCharacteristic(characteristicPath string, value []byte) error {
+	return b.writeGattValue(characteristicPath, dbusBluezGattCharacteristicIface, value)
+}
+
+// ReadDescriptor reads the value of the GATT descriptor.
+func (b *Bluez) ReadDescriptor(descriptorPath string) ([]byte, error) {
+	return b.readGattValue(descriptorPath, dbusBluezGattDescriptorIface)
+}
+
+// WriteDescriptor writes the value to the GATT descriptor.
+func (b *Bluez) WriteDescriptor(descriptorPath string, value []byte) error {
+	return b.writeGattValue(descriptorPath, dbusBluezGattDescriptorIface, value)
+}
+
+// StartNotify enables notifications for the GATT characteristic.
+// The changed values are sent as PropertiesChanged signals.
+func (b *Bluez) StartNotify(characteristicPath string) error {
+	return b.conn.Object(dbusBluezName, dbus.ObjectPath(characteristicPath)).
+		Call(dbusBluezGattCharacteristicIface+".StartNotify", 0).Store()
+}
+
+// StopNotify disables notifications for the GATT characteristic.
+func (b *Bluez) StopNotify(characteristicPath string) error {
+	return b.conn.Object(dbusBluezName, dbus.ObjectPath(characteristicPath)).
+		Call(dbusBluezGattCharacteristicIface+".StopNotify", 0).Store()
+}
+
+// readGattValue reads the value of a GATT characteristic or descriptor.
+func (b *Bluez) readGattValue(path, iface string) ([]byte, error) {
+	var value []byte
+
+	err := b.conn.Object(dbusBluezName, dbus.ObjectPath(path)).
+		Call(iface+".ReadValue", 0, map[string]dbus.Variant{}).
+		Store(&value)
+
+	return value, err
+}
+
+// writeGattValue writes the value to a GATT characteristic or descriptor.
+func (b *Bluez) writeGattValue(path, iface string, value []byte) error {
+	return b.conn.Object(dbusBluezName, dbus.ObjectPath(path)).
+		Call(iface+".WriteValue", 0, value, map[string]dbus.Variant{}).
+		Store()
+}
+
+// GattName returns the name of the GATT service, characteristic or descriptor
+// with the provided UUID, as listed in Services.
+func GattName(gattUUID string) string {
+	name := ServiceType(gattUUID)
+	if name == "Unknown" || name == "Vendor specific" {
+		return gattUUID
+	}
+
+	return name
+}
+
+// DecodeGattValue decodes the value of some common GATT characteristics
+// and descriptors, and returns a readable form of the value.
+func DecodeGattValue(gattUUID string, value []byte) (string, bool) {
+	parsedUUID, err := uuid.Parse(gattUUID)
+	if err != nil || len(value) == 0 {
+		return "", false
+	}
+
+	switch parsedUUID.ID() {
+	case 0x2a19:
+		return fmt.Sprintf("%d%%", value[0]), true
+
+	case 0x2a00, 0x2a24, 0x2a25, 0x2a26, 0x2a27, 0x2a28, 0x2a29, 0x2901:
+		if !utf8.Valid(value) {
+			return "", false
+		}
+
+		return strings.TrimRight(string(value), "\x00"), true
+
+	case 0x2a01:
+		if len(value) < 2 {
+			return "", false
+		}
+
+		return AppearanceName(binary.LittleEndian.Uint16(value)), true
+
+	case 0x2a37:
+		if len(value) < 2 {
+			return "", false
+		}
+
+		heartRate := uint16(value[1])
+		if value[0]&0x01 != 0 && len(value) >= 3 {
+			heartRate = binary.LittleEndian.Uint16(value[1:3])
+		}
+
+		return fmt.Sprintf("%d bpm", heartRate), true
+
+	case 0x2902:
+		if len(value) < 2 {
+			return "", false
+		}
+
+		config := binary.LittleEndian.Uint16(value)
+		switch {
+		case config&0x01 != 0:
+			return "Notifications enabled", true
+
+		case config&0x02 != 0:
+			return "Indications enabled", true
+		}
+
+		return "Notifications disabled", true
+	}
+
+	return "", false
+}
@@ -0,0 +1,61 @@
+package bluez
+
+import "github.com/godbus/dbus/v5"
+
+// NetworkProperties holds the network connection properties of a device.
+type NetworkProperties struct {
+	Connected bool
+	Interface string
+	UUID      string
+}
+
+// ConnectNetwork connects to the network of the device with the provided
+// role ("nap", "gn" or "panu"), and returns the network interface.
+func (b *Bluez) ConnectNetwork(devicePath, role string) (string, error) {
+	var iface string
+
+	err := b.conn.Object(dbusBluezName, dbus.ObjectPath(devicePath)).
+		Call(dbusBluezNetworkIface+".Connect", 0, role).
+		Store(&iface)
+
+	return iface, err
+}
+
+// DisconnectNetwork disconnects from the network of the device.
+func (b *Bluez) DisconnectNetwork(devicePath string) error {
+	return b.conn.Object(dbusBluezName, dbus.ObjectPath(devicePath)).
+		Call(dbusBluezNetworkIface+".Disconnect", 0).
+		Store()
+}
+
+// GetNetworkProperties returns the network connection properties of the device.
+func (b *Bluez) GetNetworkProperties(devicePath string) (NetworkProperties, error) {
+	var properties NetworkProperties
+
+	props := make(map[string]dbus.Variant)
+	if err := b.conn.Object(dbusBluezName, dbus.ObjectPath(devicePath)).
+		Call(dbusPropertiesGetAllPath, 0, dbusBluezNetworkIface).
+		Store(&props); err != nil {
+		return properties, err
+	}
+
+	props["Connected"].Store(&properties.Connected)
+	props["Interface"].Store(&properties.Interface)
+	props["UUID"].Store(&properties.UUID)
+
+	return properties, nil
+}
+
+// GetDeviceFromAddress returns the device with the provided address
+// from any of the adapters.
+func (b *Bluez) GetDeviceFromAddress(address string) (Device, bool) {
+	for _, adapter := range b.GetAdapters() {
+		for _, device := range b.GetAdapterDevices(adapter.Path) {
+			if device.Address == address {
+				return device, true
+			}
+		}
+	}
+
+	return Device{}, false
+}
@@ -0,0 +1,20 @@
+package bluez
+
+import "github.com/godbus/dbus/v5"
+
+const dbusBluezNetworkServerIface = "org.bluez.NetworkServer1"
+
+// RegisterNetworkServer registers the adapter as a NAP server,
+// and adds the connected clients to the provided bridge interface.
+func (b *Bluez) RegisterNetworkServer(adapterPath, bridge string) error {
+	return b.conn.Object(dbusBluezName, dbus.ObjectPath(adapterPath)).
+		Call(dbusBluezNetworkServerIface+".Register", 0, "nap", bridge).
+		Store()
+}
+
+// UnregisterNetworkServer unregisters the adapter as a NAP server.
+func (b *Bluez) UnregisterNetworkServer(adapterPath string) error {
+	return b.conn.Object(dbusBluezName, dbus.ObjectPath(adapterPath)).
+		Call(dbusBluezNetworkServerIface+".Unregister", 0, "nap").
+		Store()
+}
@@ -222,6 +222,20 @@ func (o *Obex) GetSessionProperties(sessionPath dbus.ObjectPath, sprop ...map[st
 	return sessionProperties, DecodeVariantMap(props, &sessionProperties)
 }
 
+// GetTransferStatus returns the current status of the transfer.
+func (o *Obex) GetTransferStatus(transferPath dbus.ObjectPath) (string, error) {
+	var status string
+
+	variant, err := o.conn.Object(dbusObexName, transferPath).GetProperty(dbusObexTransferIface + ".Status")
+	if err != nil {
+		return "", err
+	}
+
+	err = variant.Store(&status)
+
+	return status, err
+}
+
 // GetTransferProperties converts a map of transfer properties to ObexTransferProperties.
 func (o *Obex) GetTransferProperties(props map[string]dbus.Variant) (ObexTransferProperties, error) {
 	var obexTransferProperties ObexTransferProperties
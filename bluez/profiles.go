@@ -1,7 +1,10 @@
 package bluez
 
 import (
-	"github.com/mafik/pulseaudio"
+	"net"
+	"time"
+
+	"github.com/jfreymuth/pulse/proto"
 	"github.com/pkg/errors"
 )
 
@@ -11,56 +14,453 @@ type AudioProfile struct {
 	Description string
 	Index       uint32
 	Active      bool
+
+	Sinks, Sources, Priority uint32
+}
+
+// AudioEndpoint stores the sink or source information
+// of a sound card.
+type AudioEndpoint struct {
+	Name        string
+	Description string
+	Index       uint32
+	CardIndex   uint32
+	Volume      proto.ChannelVolumes
+	Mute        bool
+}
+
+// pulseClient stores a connection to the pulseaudio server.
+type pulseClient struct {
+	conn net.Conn
+
+	*proto.Client
+}
+
+// newPulseClient connects to the pulseaudio server.
+func newPulseClient() (*pulseClient, error) {
+	client, conn, err := proto.Connect("")
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot connect to the pulseaudio server")
+	}
+
+	client.Callback = func(msg interface{}) {}
+
+	err = client.Request(&proto.SetClientName{
+		Props: proto.PropList{
+			"application.name": proto.PropListString("bluetuith"),
+		},
+	}, &proto.SetClientNameReply{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &pulseClient{conn, client}, nil
+}
+
+// Close closes the connection to the pulseaudio server.
+func (p *pulseClient) Close() {
+	p.conn.Close()
+}
+
+// getCard returns the sound card of the device.
+func (p *pulseClient) getCard(deviceAddress string) (*proto.GetCardInfoReply, error) {
+	var cards proto.GetCardInfoListReply
+
+	if err := p.Request(&proto.GetCardInfoList{}, &cards); err != nil {
+		return nil, err
+	}
+
+	for _, card := range cards {
+		if cardAddress(card) == deviceAddress {
+			return card, nil
+		}
+	}
+
+	return nil, errors.New("No sound card found")
+}
+
+// getSinks returns the sinks of the sound card.
+func (p *pulseClient) getSinks(cardIndex uint32) ([]AudioEndpoint, error) {
+	var endpoints []AudioEndpoint
+	var sinks proto.GetSinkInfoListReply
+
+	if err := p.Request(&proto.GetSinkInfoList{}, &sinks); err != nil {
+		return nil, err
+	}
+
+	for _, sink := range sinks {
+		if sink.CardIndex != cardIndex {
+			continue
+		}
+
+		endpoints = append(endpoints, AudioEndpoint{
+			Name:        sink.SinkName,
+			Description: sink.Device,
+			Index:       sink.SinkIndex,
+			CardIndex:   sink.CardIndex,
+			Volume:      sink.ChannelVolumes,
+			Mute:        sink.Mute,
+		})
+	}
+
+	return endpoints, nil
+}
+
+// getSources returns the sources of the sound card.
+// Monitor sources are skipped.
+func (p *pulseClient) getSources(cardIndex uint32) ([]AudioEndpoint, error) {
+	var endpoints []AudioEndpoint
+	var sources proto.GetSourceInfoListReply
+
+	if err := p.Request(&proto.GetSourceInfoList{}, &sources); err != nil {
+		return nil, err
+	}
+
+	for _, source := range sources {
+		if source.CardIndex != cardIndex || source.MonitorSourceIndex != proto.Undefined {
+			continue
+		}
+
+		endpoints = append(endpoints, AudioEndpoint{
+			Name:        source.SourceName,
+			Description: source.Device,
+			Index:       source.SourceIndex,
+			CardIndex:   source.CardIndex,
+			Volume:      source.ChannelVolumes,
+			Mute:        source.Mute,
+		})
+	}
+
+	return endpoints, nil
 }
 
 // ListAudioProfiles lists audio profiles of a sound card.
 func ListAudioProfiles(deviceAddress string) ([]AudioProfile, error) {
 	var profiles []AudioProfile
 
-	client, err := pulseaudio.NewClient()
+	client, err := newPulseClient()
 	if err != nil {
 		return nil, err
 	}
 	defer client.Close()
 
-	cards, err := client.Cards()
+	card, err := client.getCard(deviceAddress)
+	if err != nil {
+		return nil, errors.New("No profiles found")
+	}
+
+	for _, profile := range card.Profiles {
+		if profile.Available != 1 {
+			continue
+		}
+
+		profiles = append(profiles, AudioProfile{
+			Index:       card.CardIndex,
+			Name:        profile.Name,
+			Description: profile.Description,
+			Active:      profile.Name == card.ActiveProfileName,
+			Sinks:       profile.NumSinks,
+			Sources:     profile.NumSources,
+			Priority:    profile.Priority,
+		})
+	}
+
+	if profiles == nil {
+		return nil, errors.New("No profiles found")
+	}
+
+	return profiles, nil
+}
+
+// ListAudioSinks lists the sinks of the device's sound card.
+func ListAudioSinks(deviceAddress string) ([]AudioEndpoint, error) {
+	client, err := newPulseClient()
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	for _, card := range cards {
-		if addr, ok := card.PropList["device.string"]; ok {
-			if addr != deviceAddress {
+	card, err := client.getCard(deviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.getSinks(card.CardIndex)
+}
+
+// ListAudioSources lists the sources of the device's sound card.
+func ListAudioSources(deviceAddress string) ([]AudioEndpoint, error) {
+	client, err := newPulseClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	card, err := client.getCard(deviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.getSources(card.CardIndex)
+}
+
+// SetAudioProfile sets an audio profile for a sound card.
+func (a AudioProfile) SetAudioProfile() error {
+	client, err := newPulseClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.Request(&proto.SetCardProfile{
+		CardIndex:   a.Index,
+		ProfileName: a.Name,
+	}, nil)
+}
+
+// SetDeviceAudioProfile sets the audio profile with the provided name
+// for the device's sound card.
+func SetDeviceAudioProfile(deviceAddress, profileName string) error {
+	client, err := newPulseClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	card, err := client.getCard(deviceAddress)
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range card.Profiles {
+		if profile.Name != profileName {
+			continue
+		}
+
+		if profile.Available != 1 {
+			return errors.New("Audio profile " + profileName + " is not available")
+		}
+
+		if profile.Name == card.ActiveProfileName {
+			return nil
+		}
+
+		return client.Request(&proto.SetCardProfile{
+			CardIndex:   card.CardIndex,
+			ProfileName: profile.Name,
+		}, nil)
+	}
+
+	return errors.New("Audio profile " + profileName + " not found")
+}
+
+// RouteAudio sets the sink and source of the device's sound card
+// as the default sink and source, and moves all existing playback
+// and capture streams to them.
+func RouteAudio(deviceAddress string) error {
+	var unmoved int
+
+	client, err := newPulseClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	card, err := client.getCard(deviceAddress)
+	if err != nil {
+		return err
+	}
+
+	sinks, err := client.getSinks(card.CardIndex)
+	if err != nil {
+		return err
+	}
+
+	sources, err := client.getSources(card.CardIndex)
+	if err != nil {
+		return err
+	}
+
+	if sinks == nil && sources == nil {
+		return errors.New("No sinks or sources found")
+	}
+
+	if sinks != nil {
+		sink := sinks[0]
+
+		err := client.Request(&proto.SetDefaultSink{SinkName: sink.Name}, nil)
+		if err != nil {
+			return errors.Wrap(err, "Cannot set the default sink")
+		}
+
+		var inputs proto.GetSinkInputInfoListReply
+		if err := client.Request(&proto.GetSinkInputInfoList{}, &inputs); err != nil {
+			return err
+		}
+
+		for _, input := range inputs {
+			if input.SinkIndex == sink.Index {
 				continue
 			}
 
-			for profileName, profile := range card.Profiles {
-				if profile.Available != 1 {
-					continue
-				}
-
-				profiles = append(profiles, AudioProfile{
-					Index:       card.Index,
-					Name:        profileName,
-					Description: profile.Description,
-					Active:      profile.Name == card.ActiveProfile.Name,
-				})
+			err := client.Request(&proto.MoveSinkInput{
+				SinkInputIndex: input.SinkInputIndex,
+				DeviceIndex:    sink.Index,
+			}, nil)
+			if err != nil {
+				unmoved++
+			}
+		}
+	}
+
+	if sources != nil {
+		source := sources[0]
+
+		err := client.Request(&proto.SetDefaultSource{SourceName: source.Name}, nil)
+		if err != nil {
+			return errors.Wrap(err, "Cannot set the default source")
+		}
+
+		var outputs proto.GetSourceOutputInfoListReply
+		if err := client.Request(&proto.GetSourceOutputInfoList{}, &outputs); err != nil {
+			return err
+		}
+
+		for _, output := range outputs {
+			if output.SourceIndex == source.Index {
+				continue
 			}
 
-			return profiles, nil
+			err := client.Request(&proto.MoveSourceOutput{
+				SourceOutputIndex: output.SourceOutpuIndex,
+				DeviceIndex:       source.Index,
+			}, nil)
+			if err != nil {
+				unmoved++
+			}
 		}
 	}
 
-	return nil, errors.New("No profiles found")
+	if unmoved > 0 {
+		return errors.Errorf("%d stream(s) could not be moved", unmoved)
+	}
+
+	return nil
 }
 
-// SetAudioProfile sets an audio profile for a sound card.
-func (a AudioProfile) SetAudioProfile() error {
-	client, err := pulseaudio.NewClient()
+// MoveCaptureStream moves the capture stream to the source of the device's sound card.
+func MoveCaptureStream(deviceAddress string, streamIndex uint32) error {
+	client, err := newPulseClient()
 	if err != nil {
 		return err
 	}
 	defer client.Close()
 
-	return client.SetCardProfile(a.Index, a.Name)
+	card, err := client.getCard(deviceAddress)
+	if err != nil {
+		return err
+	}
+
+	sources, err := client.getSources(card.CardIndex)
+	if err != nil {
+		return err
+	}
+	if sources == nil {
+		return errors.New("No sources found")
+	}
+
+	err = client.Request(&proto.MoveSourceOutput{
+		SourceOutputIndex: streamIndex,
+		DeviceIndex:       sources[0].Index,
+	}, nil)
+	if err != nil {
+		return errors.Wrap(err, "Cannot move the capture stream")
+	}
+
+	return nil
+}
+
+// WaitForAudioCard waits for the device's sound card to appear.
+func WaitForAudioCard(deviceAddress string, timeout time.Duration) error {
+	return waitForAudio(timeout, func(client *pulseClient) bool {
+		_, err := client.getCard(deviceAddress)
+
+		return err == nil
+	})
+}
+
+// WaitForAudioEndpoints waits for the sinks or sources of the
+// device's sound card to appear.
+func WaitForAudioEndpoints(deviceAddress string, timeout time.Duration) error {
+	return waitForAudio(timeout, func(client *pulseClient) bool {
+		card, err := client.getCard(deviceAddress)
+		if err != nil {
+			return false
+		}
+
+		sinks, _ := client.getSinks(card.CardIndex)
+		sources, _ := client.getSources(card.CardIndex)
+
+		return sinks != nil || sources != nil
+	})
+}
+
+// WaitForAudioSource waits for a source of the device's sound card to appear.
+func WaitForAudioSource(deviceAddress string, timeout time.Duration) error {
+	return waitForAudio(timeout, func(client *pulseClient) bool {
+		card, err := client.getCard(deviceAddress)
+		if err != nil {
+			return false
+		}
+
+		sources, _ := client.getSources(card.CardIndex)
+
+		return sources != nil
+	})
+}
+
+// waitForAudio listens for card, sink and source events until
+// the provided check succeeds or the timeout expires.
+func waitForAudio(timeout time.Duration, check func(client *pulseClient) bool) error {
+	client, err := newPulseClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	events := make(chan struct{}, 1)
+	client.Callback = func(msg interface{}) {
+		if _, ok := msg.(*proto.SubscribeEvent); !ok {
+			return
+		}
+
+		select {
+		case events <- struct{}{}:
+		default:
+		}
+	}
+
+	err = client.Request(&proto.Subscribe{
+		Mask: proto.SubscriptionMaskCard | proto.SubscriptionMaskSink | proto.SubscriptionMaskSource,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	for {
+		if check(client) {
+			return nil
+		}
+
+		select {
+		case <-events:
+
+		case <-t.C:
+			return errors.New("Timed out waiting for the sound card")
+		}
+	}
 }
@@ -0,0 +1,178 @@
+package bluez
+
+import (
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/pkg/errors"
+)
+
+const (
+	dbusBluezProfileManagerIface = "org.bluez.ProfileManager1"
+	dbusBluezProfileIface        = "org.bluez.Profile1"
+
+	serialProfilePath = dbus.ObjectPath("/org/bluez/bluetuith/serial")
+)
+
+// SerialConnection holds a RFCOMM connection to a device's serial port.
+type SerialConnection struct {
+	DevicePath string
+	File       *os.File
+}
+
+// SerialHandler handles the connections to the serial port profile.
+// OnConnect is called when a new connection is established, and the
+// connection is rejected if it returns an error. OnDisconnect is called
+// when BlueZ requests a device to be disconnected.
+type SerialHandler struct {
+	OnConnect    func(connection SerialConnection) error
+	OnDisconnect func(devicePath string)
+}
+
+// serialProfile holds the exported serial port profile.
+type serialProfile struct {
+	conn    *dbus.Conn
+	handler SerialHandler
+}
+
+var (
+	serialPortProfile     *serialProfile
+	serialPortProfileLock sync.Mutex
+)
+
+// SerialPortUUID returns the UUID of the serial port profile.
+func SerialPortUUID() string {
+	return serviceUUID(SERIAL_PORT_SVCLASS_ID)
+}
+
+// RegisterSerialProfile exports and registers the serial port profile, so that
+// the RFCOMM connections to and from devices are passed to the handler.
+func (b *Bluez) RegisterSerialProfile(handler SerialHandler) error {
+	serialPortProfileLock.Lock()
+	defer serialPortProfileLock.Unlock()
+
+	if serialPortProfile != nil {
+		serialPortProfile.handler = handler
+		return nil
+	}
+
+	profile := &serialProfile{conn: b.conn, handler: handler}
+
+	if err := b.conn.Export(profile, serialProfilePath, dbusBluezProfileIface); err != nil {
+		return err
+	}
+
+	node := &introspect.Node{
+		Name: string(serialProfilePath),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{
+				Name:    dbusBluezProfileIface,
+				Methods: introspect.Methods(profile),
+			},
+		},
+	}
+	if err := b.conn.Export(introspect.NewIntrospectable(node), serialProfilePath, dbusIntrospectableIface); err != nil {
+		profile.unexport()
+		return err
+	}
+
+	options := map[string]dbus.Variant{
+		"Name":                  dbus.MakeVariant("bluetuith Serial Port"),
+		"AutoConnect":           dbus.MakeVariant(false),
+		"RequireAuthentication": dbus.MakeVariant(true),
+	}
+
+	err := b.conn.Object(dbusBluezName, "/org/bluez").
+		Call(dbusBluezProfileManagerIface+".RegisterProfile", 0, serialProfilePath, SerialPortUUID(), options).
+		Store()
+	if err != nil {
+		profile.unexport()
+		return err
+	}
+
+	serialPortProfile = profile
+
+	return nil
+}
+
+// UnregisterSerialProfile unregisters and removes the serial port profile.
+func (b *Bluez) UnregisterSerialProfile() error {
+	serialPortProfileLock.Lock()
+	profile := serialPortProfile
+	serialPortProfile = nil
+	serialPortProfileLock.Unlock()
+
+	if profile == nil {
+		return nil
+	}
+
+	defer profile.unexport()
+
+	return b.conn.Object(dbusBluezName, "/org/bluez").
+		Call(dbusBluezProfileManagerIface+".UnregisterProfile", 0, serialProfilePath).
+		Store()
+}
+
+// NewConnection is called by BlueZ when a RFCOMM connection to the serial port is established.
+func (s *serialProfile) NewConnection(device dbus.ObjectPath, fd dbus.UnixFD, properties map[string]dbus.Variant) *dbus.Error {
+	// The socket is set to non-blocking mode, so that pending reads
+	// are interrupted when the connection is closed.
+	syscall.SetNonblock(int(fd), true)
+	file := os.NewFile(uintptr(fd), "rfcomm:"+string(device))
+
+	serialPortProfileLock.Lock()
+	handler := s.handler
+	serialPortProfileLock.Unlock()
+
+	if handler.OnConnect == nil {
+		file.Close()
+		return dbus.MakeFailedError(errors.New("Connection rejected"))
+	}
+
+	if err := handler.OnConnect(SerialConnection{DevicePath: string(device), File: file}); err != nil {
+		file.Close()
+		return dbus.MakeFailedError(err)
+	}
+
+	return nil
+}
+
+// RequestDisconnection is called by BlueZ when the device is disconnected from the serial port.
+func (s *serialProfile) RequestDisconnection(device dbus.ObjectPath) *dbus.Error {
+	serialPortProfileLock.Lock()
+	handler := s.handler
+	serialPortProfileLock.Unlock()
+
+	if handler.OnDisconnect != nil {
+		handler.OnDisconnect(string(device))
+	}
+
+	return nil
+}
+
+// Release is called by BlueZ when the profile is unregistered.
+func (s *serialProfile) Release() *dbus.Error {
+	serialPortProfileLock.Lock()
+	if serialPortProfile == s {
+		serialPortProfile = nil
+	}
+	serialPortProfileLock.Unlock()
+
+	go s.unexport()
+
+	return nil
+}
+
+// unexport removes the exported serial port profile object.
+func (s *serialProfile) unexport() {
+	for _, iface := range []string{
+		dbusBluezProfileIface,
+		dbusIntrospectableIface,
+	} {
+		s.conn.Export(nil, serialProfilePath, iface)
+	}
+}
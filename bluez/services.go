@@ -57,6 +57,7 @@ const (
 	PBAP_PCE_SVCLASS_ID             = 0x112e
 	PBAP_PSE_SVCLASS_ID             = 0x112f
 	PBAP_SVCLASS_ID                 = 0x1130
+	HEADSET_HS_SVCLASS_ID           = 0x1131
 	MAP_MSE_SVCLASS_ID              = 0x1132
 	MAP_MCE_SVCLASS_ID              = 0x1133
 	MAP_SVCLASS_ID                  = 0x1134
@@ -0,0 +1,150 @@
+package bluez
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// VendorDatabase stores the company names from the systemd hardware database,
+// mapped to the Bluetooth SIG company identifiers and the IEEE OUIs.
+type VendorDatabase struct {
+	companies map[uint64]string
+	ouis      map[string]string
+
+	load sync.Once
+}
+
+// hwdbDirs holds the directories where the systemd hardware database files are stored.
+var hwdbDirs = []string{"/etc/udev/hwdb.d", "/usr/lib/udev/hwdb.d", "/lib/udev/hwdb.d"}
+
+var vendorDatabase VendorDatabase
+
+// companyIdentifiers matches some common Bluetooth SIG company identifiers
+// to the company names. This is used if the hardware database is not available.
+var companyIdentifiers = map[uint64]string{
+	0x0002: "Intel Corp.",
+	0x0006: "Microsoft",
+	0x000a: "Qualcomm Technologies International, Ltd.",
+	0x000f: "Broadcom Corporation",
+	0x001d: "Qualcomm",
+	0x004c: "Apple, Inc.",
+	0x0067: "GN Netcom",
+	0x0075: "Samsung Electronics Co. Ltd.",
+	0x0087: "Garmin International, Inc.",
+	0x009e: "Bose Corporation",
+	0x00e0: "Google",
+	0x012d: "Sony Corporation",
+	0x0171: "Amazon.com Services, Inc.",
+}
+
+// DeviceVendor returns the vendor of the device, parsed from its modalias.
+// If the device has no bluetooth modalias, the vendor is looked up using
+// the OUI of the device's public address. If the vendor is not known,
+// the vendor ID is returned.
+func DeviceVendor(device Device) string {
+	source, ids, ok := strings.Cut(device.Modalias, ":")
+	if !ok || len(ids) < 5 || ids[0] != 'v' {
+		return ouiVendor(device)
+	}
+
+	vendorID, err := strconv.ParseUint(ids[1:5], 16, 16)
+	if err != nil {
+		return ouiVendor(device)
+	}
+
+	if source == "bluetooth" {
+		return CompanyName(vendorID)
+	}
+
+	if company := ouiVendor(device); company != "" {
+		return company
+	}
+
+	return fmt.Sprintf("%s:%04X", source, vendorID)
+}
+
+// CompanyName returns the name of the company with the provided
+// Bluetooth SIG company identifier. If the company is not known,
+// the identifier is returned.
+func CompanyName(id uint64) string {
+	vendorDatabase.load.Do(loadVendorDatabase)
+
+	if company, ok := vendorDatabase.companies[id]; ok {
+		return company
+	}
+
+	if company, ok := companyIdentifiers[id]; ok {
+		return company
+	}
+
+	return fmt.Sprintf("0x%04X", id)
+}
+
+// ouiVendor returns the company which was assigned the OUI of the device's address.
+// Random addresses do not have an OUI, so no company is returned for them.
+func ouiVendor(device Device) string {
+	if device.AddressType == "random" || len(device.Address) < 8 {
+		return ""
+	}
+
+	vendorDatabase.load.Do(loadVendorDatabase)
+
+	return vendorDatabase.ouis[strings.ToUpper(strings.ReplaceAll(device.Address[:8], ":", ""))]
+}
+
+// loadVendorDatabase loads the Bluetooth SIG company identifiers and the
+// IEEE OUIs from the systemd hardware database files, if they exist.
+func loadVendorDatabase() {
+	vendorDatabase.companies = make(map[uint64]string)
+	vendorDatabase.ouis = make(map[string]string)
+
+	for _, dir := range hwdbDirs {
+		parseHwdb(filepath.Join(dir, "20-bluetooth-vendor-product.hwdb"), "bluetooth:v", "ID_VENDOR_FROM_DATABASE=",
+			func(key, value string) {
+				if id, err := strconv.ParseUint(key, 16, 16); err == nil {
+					vendorDatabase.companies[id] = value
+				}
+			},
+		)
+
+		parseHwdb(filepath.Join(dir, "20-OUI.hwdb"), "OUI:", "ID_OUI_FROM_DATABASE=",
+			func(key, value string) {
+				vendorDatabase.ouis[strings.ToUpper(key)] = value
+			},
+		)
+	}
+}
+
+// parseHwdb parses the hardware database file, and passes the key of each
+// match with the provided prefix and its value to the store function.
+func parseHwdb(path, matchPrefix, valuePrefix string, store func(key, value string)) {
+	var key string
+
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, matchPrefix):
+			key = strings.TrimSuffix(strings.TrimPrefix(line, matchPrefix), "*")
+
+		case key != "" && strings.HasPrefix(strings.TrimSpace(line), valuePrefix):
+			store(key, strings.TrimPrefix(strings.TrimSpace(line), valuePrefix))
+			key = ""
+
+		case line == "":
+			key = ""
+		}
+	}
+}
@@ -15,8 +15,11 @@ func Init(bluez *bluez.Bluez) {
 	validateKeybindings()
 	cmdOptionGenerate()
 	cmdOptionTheme()
+	cmdOptionColumns()
 
 	cmdOptionGsm()
+	cmdOptionNetworkBackend()
+	cmdOptionDaemon()
 
 	cmdOptionReceiveDir()
 }
@@ -7,14 +7,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/hjson/hjson-go/v4"
+	hjsonparser "github.com/knadh/koanf/parsers/hjson"
+	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
 )
 
 // Config describes the configuration for the app.
 type Config struct {
 	path string
+	lock sync.RWMutex
 
 	*koanf.Koanf
 }
@@ -108,26 +112,154 @@ func ConfigPath(configType string) (string, error) {
 	return confPath, nil
 }
 
+// ReceiveDir returns the directory where received files are stored.
+// If the directory is not specified, it automatically creates a directory
+// in the user's home path.
+func ReceiveDir() (string, error) {
+	userpath := GetProperty("receive-dir")
+	if userpath != "" {
+		return userpath, nil
+	}
+
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	userpath = filepath.Join(homedir, "bluetuith")
+
+	if _, err := os.Stat(userpath); err != nil {
+		err = os.Mkdir(userpath, 0700)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return userpath, nil
+}
+
 // GetProperty returns the value for the given property.
 func GetProperty(property string) string {
+	config.lock.RLock()
+	defer config.lock.RUnlock()
+
 	return config.String(property)
 }
 
 // GetPropertyMap returns a map of values for the given property.
 func GetPropertyMap(property string) map[string]string {
+	config.lock.RLock()
+	defer config.lock.RUnlock()
+
 	return config.StringMap(property)
 }
 
 // AddProperty adds a property and its value to the properties store.
 func AddProperty(property string, value interface{}) {
+	config.lock.Lock()
+	defer config.lock.Unlock()
+
 	config.Set(property, value)
 }
 
 // IsPropertyEnabled returns if a property is enabled.
 func IsPropertyEnabled(property string) bool {
+	config.lock.RLock()
+	defer config.lock.RUnlock()
+
 	return config.Bool(property)
 }
 
+// GetDeviceProperty returns the value for the given property of a device.
+func GetDeviceProperty(address, property string) string {
+	return GetProperty(deviceProperty(address, property))
+}
+
+// IsDevicePropertyEnabled returns if a property of a device is enabled.
+func IsDevicePropertyEnabled(address, property string) bool {
+	return IsPropertyEnabled(deviceProperty(address, property))
+}
+
+// SaveDeviceProperty adds a property of a device to the properties store,
+// and saves it to the configuration file.
+func SaveDeviceProperty(address, property string, value interface{}) error {
+	return SaveProperty(deviceProperty(address, property), value)
+}
+
+// SaveDeviceProperties saves the given properties of a device at once.
+func SaveDeviceProperties(address string, properties map[string]interface{}) error {
+	deviceProperties := make(map[string]interface{}, len(properties))
+	for property, value := range properties {
+		deviceProperties[deviceProperty(address, property)] = value
+	}
+
+	return SaveProperties(deviceProperties)
+}
+
+// SaveProperty adds a property to the properties store, and saves it
+// to the configuration file. Only the values present in the configuration
+// file are written back, so that command-line options are not persisted.
+func SaveProperty(property string, value interface{}) error {
+	return SaveProperties(map[string]interface{}{property: value})
+}
+
+// SaveProperties adds the properties to the properties store, and saves
+// them to the configuration file with a single write.
+func SaveProperties(properties map[string]interface{}) error {
+	config.lock.Lock()
+	defer config.lock.Unlock()
+
+	for property, value := range properties {
+		config.Set(property, value)
+	}
+
+	conf, err := ConfigPath("bluetuith.conf")
+	if err != nil {
+		return err
+	}
+
+	saved := koanf.New(".")
+	if err := saved.Load(file.Provider(conf), hjsonparser.Parser()); err != nil {
+		return err
+	}
+	for property, value := range properties {
+		saved.Set(property, value)
+	}
+
+	data, err := hjson.Marshal(saved.Raw())
+	if err != nil {
+		return err
+	}
+
+	return writeConfig(conf, data)
+}
+
+// GetAdapterProperty returns the value for the given property of an adapter.
+func GetAdapterProperty(adapterID, property string) string {
+	return GetProperty(adapterProperty(adapterID, property))
+}
+
+// IsAdapterPropertyEnabled returns if a property of an adapter is enabled.
+func IsAdapterPropertyEnabled(adapterID, property string) bool {
+	return IsPropertyEnabled(adapterProperty(adapterID, property))
+}
+
+// SaveAdapterProperty adds a property of an adapter to the properties store,
+// and saves it to the configuration file.
+func SaveAdapterProperty(adapterID, property string, value interface{}) error {
+	return SaveProperty(adapterProperty(adapterID, property), value)
+}
+
+// adapterProperty returns the configuration key for the given property of an adapter.
+func adapterProperty(adapterID, property string) string {
+	return "adapters." + adapterID + "." + property
+}
+
+// deviceProperty returns the configuration key for the given property of a device.
+func deviceProperty(address, property string) string {
+	return "devices." + address + "." + property
+}
+
 // generate generates and updates the configuration.
 // Any existing values are appended to it.
 func generate() {
@@ -153,6 +285,12 @@ func generate() {
 	}
 	genMap["theme"] = theme
 
+	for _, section := range []string{"adapters", "devices", "notifications", "view"} {
+		if value := config.Get(section); value != nil {
+			genMap[section] = value
+		}
+	}
+
 	data, err := hjson.Marshal(genMap)
 	if err != nil {
 		PrintError(err.Error())
@@ -163,20 +301,30 @@ func generate() {
 		PrintError(err.Error())
 	}
 
-	file, err := os.OpenFile(conf, os.O_WRONLY|os.O_TRUNC, os.ModePerm)
-	if err != nil {
+	if err := writeConfig(conf, data); err != nil {
 		PrintError(err.Error())
 	}
-	defer file.Close()
+}
 
-	_, err = file.Write(data)
+// writeConfig writes the data to the configuration file. Since the
+// configuration may hold the dialup network secrets of devices, the
+// file is made readable only by the user.
+func writeConfig(conf string, data []byte) error {
+	file, err := os.OpenFile(conf, os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
-		PrintError(err.Error())
+		return err
+	}
+	defer file.Close()
+
+	if err := file.Chmod(0600); err != nil {
+		return err
 	}
 
-	if err := file.Sync(); err != nil {
-		PrintError(err.Error())
+	if _, err = file.Write(data); err != nil {
+		return err
 	}
+
+	return file.Sync()
 }
 
 // parseOldConfig parses and stores values from the old configuration.
@@ -42,6 +42,41 @@ var options = []Option{
 		Name:        "gsm-number",
 		Description: "Specify GSM number to dial. (Required for DUN)",
 	},
+	{
+		Name:        "network-backend",
+		Description: "Specify the network backend to use. (networkmanager or bluez, the default is to fallback to bluez if networkmanager is unavailable)",
+	},
+	{
+		Name:        "network-hook",
+		Description: "Specify a command to run when a network interface is connected or disconnected via the bluez network backend.",
+	},
+	{
+		Name:        "network-networkd",
+		Description: "Reconfigure the network interface via systemd-networkd when connected via the bluez network backend.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "daemon",
+		Description: "Run the pairing and file transfer agents without the UI.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "daemon-pairing",
+		Description: "Specify the policy to answer pairing requests in daemon mode. (trusted, accept or reject, the default is to accept only trusted devices)",
+	},
+	{
+		Name:        "daemon-receive",
+		Description: "Specify the policy to answer file transfer requests in daemon mode. (trusted, accept or reject, the default is to accept only trusted devices)",
+	},
+	{
+		Name:        "daemon-log",
+		Description: "Specify a file to log the decisions of the daemon to. (the default is to log to stderr)",
+	},
+	{
+		Name:        "daemon-trust",
+		Description: "Mark devices as trusted once pairing is confirmed in daemon mode.",
+		IsBoolean:   true,
+	},
 	{
 		Name:        "adapter-states",
 		Description: "Specify adapter states to enable/disable. (For example, 'powered:yes,discoverable:yes,pairable:yes,scan:no')",
@@ -50,6 +85,10 @@ var options = []Option{
 		Name:        "connect-bdaddr",
 		Description: "Specify device address to connect (For example, 'AA:BB:CC:DD:EE:FF')",
 	},
+	{
+		Name:        "columns",
+		Description: "Specify the columns of the device table, in order. (For example, 'name,address,rssi,battery')",
+	},
 	{
 		Name:        "theme",
 		Description: "Specify a theme in the HJSON format. (For example, '{ Adapter: \"red\" }')",
@@ -118,6 +157,21 @@ func parse() {
 			case "gsm-number":
 				s += " <number>"
 
+			case "network-backend":
+				s += " <backend>"
+
+			case "network-hook":
+				s += " <command>"
+
+			case "daemon-pairing", "daemon-receive":
+				s += " <policy>"
+
+			case "daemon-log":
+				s += " <file>"
+
+			case "columns":
+				s += " [<column>]"
+
 			case "set-theme":
 				s += " <theme>"
 			}
@@ -329,6 +383,86 @@ func cmdOptionGsm() {
 	AddProperty("gsm-number", number)
 }
 
+func cmdOptionNetworkBackend() {
+	optionNetworkBackend := GetProperty("network-backend")
+
+	switch optionNetworkBackend {
+	case "", "networkmanager", "bluez":
+		return
+	}
+
+	PrintError(
+		fmt.Sprintf(
+			"Provided network backend '%s' is incorrect.\nValid backends are 'networkmanager, bluez'.",
+			optionNetworkBackend,
+		),
+	)
+}
+
+func cmdOptionDaemon() {
+	for _, option := range []string{"daemon-pairing", "daemon-receive"} {
+		optionPolicy := GetProperty(option)
+
+		switch optionPolicy {
+		case "", "trusted", "accept", "reject":
+			continue
+		}
+
+		PrintError(
+			fmt.Sprintf(
+				"Provided policy '%s' for '%s' is incorrect.\nValid policies are 'trusted, accept, reject'.",
+				optionPolicy, option,
+			),
+		)
+	}
+}
+
+func cmdOptionColumns() {
+	optionColumns := GetProperty("columns")
+	if optionColumns == "" {
+		return
+	}
+
+	columnOptions := []string{
+		"name",
+		"properties",
+		"address",
+		"address-type",
+		"rssi",
+		"battery",
+		"type",
+		"profile",
+		"flags",
+		"last-seen",
+		"vendor",
+		"volume",
+	}
+
+	var columns []string
+
+	for _, column := range strings.Split(optionColumns, ",") {
+		column = strings.TrimSpace(column)
+
+		for _, c := range columnOptions {
+			if column == c {
+				goto AddColumn
+			}
+		}
+		PrintError(
+			fmt.Sprintf(
+				"Provided column '%s' is incorrect.\nValid columns are '%s'.",
+				column,
+				strings.Join(columnOptions, ", "),
+			),
+		)
+
+	AddColumn:
+		columns = append(columns, column)
+	}
+
+	AddProperty("columns", strings.Join(columns, ","))
+}
+
 func cmdOptionTheme() {
 	if !config.Exists("theme") {
 		return
@@ -45,13 +45,37 @@ const (
 	KeyAdapterToggleDiscoverable   Key = "AdapterToggleDiscoverable"
 	KeyAdapterTogglePairable       Key = "AdapterTogglePairable"
 	KeyAdapterToggleScan           Key = "AdapterToggleScan"
+	KeyAdapterScanOptions          Key = "AdapterScanOptions"
+	KeyAdapterRename               Key = "AdapterRename"
+	KeyAdapterInfo                 Key = "AdapterInfo"
+	KeyAdapterToggleMultiView      Key = "AdapterToggleMultiView"
+	KeyAdapterToggleAdvertise      Key = "AdapterToggleAdvertise"
+	KeyAdapterToggleNapServer      Key = "AdapterToggleNapServer"
+	KeyAdapterNapClients           Key = "AdapterNapClients"
+	KeyAdapterNetworkProfiles      Key = "AdapterNetworkProfiles"
+	KeyDeviceRename                Key = "DeviceRename"
+	KeyDeviceReconnect             Key = "DeviceReconnect"
+	KeyDeviceProfileConnect        Key = "DeviceProfileConnect"
+	KeyDeviceGattExplorer          Key = "DeviceGattExplorer"
+	KeyDeviceAdvertisement         Key = "DeviceAdvertisement"
+	KeyDeviceSerialTerminal        Key = "DeviceSerialTerminal"
+	KeyDeviceSearch                Key = "DeviceSearch"
+	KeyDeviceFilter                Key = "DeviceFilter"
+	KeyDeviceSort                  Key = "DeviceSort"
 	KeyDeviceSendFiles             Key = "DeviceSendFiles"
 	KeyDeviceNetwork               Key = "DeviceNetwork"
+	KeyDeviceNetworkStatus         Key = "DeviceNetworkStatus"
 	KeyDeviceConnect               Key = "DeviceConnect"
 	KeyDevicePair                  Key = "DevicePair"
 	KeyDeviceTrust                 Key = "DeviceTrust"
 	KeyDeviceBlock                 Key = "DeviceBlock"
 	KeyDeviceAudioProfiles         Key = "DeviceAudioProfiles"
+	KeyDeviceAudioRoute            Key = "DeviceAudioRoute"
+	KeyDeviceAutoRouteAudio        Key = "DeviceAutoRouteAudio"
+	KeyDeviceAutoSwitchProfile     Key = "DeviceAutoSwitchProfile"
+	KeyDeviceVolumeUp              Key = "DeviceVolumeUp"
+	KeyDeviceVolumeDown            Key = "DeviceVolumeDown"
+	KeyDeviceVolumeMute            Key = "DeviceVolumeMute"
 	KeyDeviceInfo                  Key = "DeviceInfo"
 	KeyDeviceRemove                Key = "DeviceRemove"
 	KeyPlayerShow                  Key = "PlayerShow"
@@ -65,6 +89,14 @@ const (
 	KeyFilebrowserToggleHidden     Key = "FilebrowserToggleHidden"
 	KeyFilebrowserConfirmSelection Key = "FilebrowserConfirmSelection"
 	KeyProgressView                Key = "ProgressView"
+	KeyGattRead                    Key = "GattRead"
+	KeyGattWrite                   Key = "GattWrite"
+	KeyGattToggleNotify            Key = "GattToggleNotify"
+	KeyGattValueFormat             Key = "GattValueFormat"
+	KeySerialToggleEcho            Key = "SerialToggleEcho"
+	KeySerialLineEnding            Key = "SerialLineEnding"
+	KeySerialHexView               Key = "SerialHexView"
+	KeySerialToggleLog             Key = "SerialToggleLog"
 	KeyProgressTransferSuspend     Key = "ProgressTransferSuspend"
 	KeyProgressTransferResume      Key = "ProgressTransferResume"
 	KeyProgressTransferCancel      Key = "ProgressTransferCancel"
@@ -92,6 +124,8 @@ const (
 	KeyContextDevice   KeyContext = "Device"
 	KeyContextFiles    KeyContext = "Files"
 	KeyContextProgress KeyContext = "Progress"
+	KeyContextGatt     KeyContext = "Gatt"
+	KeyContextSerial   KeyContext = "Serial"
 )
 
 var (
@@ -194,6 +228,91 @@ var (
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 's', tcell.ModNone},
 		},
+		KeyAdapterScanOptions: {
+			Title:   "Scan Options",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'F', tcell.ModNone},
+		},
+		KeyAdapterRename: {
+			Title:   "Rename Adapter",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'E', tcell.ModNone},
+		},
+		KeyAdapterInfo: {
+			Title:   "Adapter Info",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'I', tcell.ModNone},
+		},
+		KeyAdapterToggleMultiView: {
+			Title:   "All Adapters",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'w', tcell.ModNone},
+		},
+		KeyAdapterToggleAdvertise: {
+			Title:   "Advertise",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'L', tcell.ModNone},
+		},
+		KeyAdapterToggleNapServer: {
+			Title:   "Share Internet",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'H', tcell.ModNone},
+		},
+		KeyAdapterNapClients: {
+			Title:   "NAP Clients",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'J', tcell.ModNone},
+		},
+		KeyAdapterNetworkProfiles: {
+			Title:   "Network Profiles",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'W', tcell.ModNone},
+		},
+		KeyDeviceRename: {
+			Title:   "Rename",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'e', tcell.ModNone},
+		},
+		KeyDeviceReconnect: {
+			Title:   "Auto Reconnect",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'k', tcell.ModNone},
+		},
+		KeyDeviceProfileConnect: {
+			Title:   "Connect Profile",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'C', tcell.ModNone},
+		},
+		KeyDeviceGattExplorer: {
+			Title:   "GATT Explorer",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'T', tcell.ModNone},
+		},
+		KeyDeviceAdvertisement: {
+			Title:   "Advertisement Data",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'D', tcell.ModNone},
+		},
+		KeyDeviceSerialTerminal: {
+			Title:   "Serial Terminal",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'X', tcell.ModNone},
+		},
+		KeyDeviceSearch: {
+			Title:   "Search",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '/', tcell.ModNone},
+		},
+		KeyDeviceFilter: {
+			Title:   "Filter",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'V', tcell.ModNone},
+		},
+		KeyDeviceSort: {
+			Title:   "Sort",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'O', tcell.ModNone},
+		},
 		KeyAdapterChange: {
 			Title:   "Change",
 			Context: KeyContextDevice,
@@ -229,11 +348,46 @@ var (
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 'n', tcell.ModNone},
 		},
+		KeyDeviceNetworkStatus: {
+			Title:   "Network Status",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'N', tcell.ModNone},
+		},
 		KeyDeviceAudioProfiles: {
 			Title:   "Audio Profiles",
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 'A', tcell.ModNone},
 		},
+		KeyDeviceAudioRoute: {
+			Title:   "Route Audio",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'R', tcell.ModNone},
+		},
+		KeyDeviceAutoRouteAudio: {
+			Title:   "Auto Route Audio",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'U', tcell.ModNone},
+		},
+		KeyDeviceAutoSwitchProfile: {
+			Title:   "Auto Switch Profile",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'Y', tcell.ModNone},
+		},
+		KeyDeviceVolumeUp: {
+			Title:   "Volume Up",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '+', tcell.ModNone},
+		},
+		KeyDeviceVolumeDown: {
+			Title:   "Volume Down",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '-', tcell.ModNone},
+		},
+		KeyDeviceVolumeMute: {
+			Title:   "Mute",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'u', tcell.ModNone},
+		},
 		KeyDeviceInfo: {
 			Title:   "Info",
 			Context: KeyContextDevice,
@@ -344,6 +498,46 @@ var (
 			Context: KeyContextProgress,
 			Kb:      Keybinding{tcell.KeyRune, 'z', tcell.ModNone},
 		},
+		KeyGattRead: {
+			Title:   "Read Value",
+			Context: KeyContextGatt,
+			Kb:      Keybinding{tcell.KeyRune, 'r', tcell.ModNone},
+		},
+		KeyGattWrite: {
+			Title:   "Write Value",
+			Context: KeyContextGatt,
+			Kb:      Keybinding{tcell.KeyRune, 'w', tcell.ModNone},
+		},
+		KeyGattToggleNotify: {
+			Title:   "Toggle Notifications",
+			Context: KeyContextGatt,
+			Kb:      Keybinding{tcell.KeyRune, 'n', tcell.ModNone},
+		},
+		KeyGattValueFormat: {
+			Title:   "Value Format",
+			Context: KeyContextGatt,
+			Kb:      Keybinding{tcell.KeyRune, 'v', tcell.ModNone},
+		},
+		KeySerialToggleEcho: {
+			Title:   "Local Echo",
+			Context: KeyContextSerial,
+			Kb:      Keybinding{tcell.KeyCtrlE, ' ', tcell.ModCtrl},
+		},
+		KeySerialLineEnding: {
+			Title:   "Line Ending",
+			Context: KeyContextSerial,
+			Kb:      Keybinding{tcell.KeyCtrlL, ' ', tcell.ModCtrl},
+		},
+		KeySerialHexView: {
+			Title:   "Hex View",
+			Context: KeyContextSerial,
+			Kb:      Keybinding{tcell.KeyCtrlT, ' ', tcell.ModCtrl},
+		},
+		KeySerialToggleLog: {
+			Title:   "Log to File",
+			Context: KeyContextSerial,
+			Kb:      Keybinding{tcell.KeyCtrlO, ' ', tcell.ModCtrl},
+		},
 	}
 
 	// Keys match the keybinding to the key type.
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/darkhz/bluetuith/agent"
 	"github.com/darkhz/bluetuith/bluez"
 	"github.com/darkhz/bluetuith/cmd"
@@ -18,13 +22,19 @@ func main() {
 		cmd.PrintError("Could not initialize bluez DBus connection", err)
 	}
 
+	if cmd.IsPropertyEnabled("daemon") {
+		startDaemon(bluezConn)
+		return
+	}
+
+	agent.SetHandler(ui.AgentHandler{})
 	if err := agent.SetupAgent(bluezConn.Conn()); err != nil {
 		cmd.PrintError("Could not setup bluez agent", err)
 	}
 
 	cmd.Init(bluezConn)
 
-	networkConn, err := network.NewNetwork()
+	networkConn, err := network.NewBackend(bluezConn)
 	if err != nil {
 		warn += "Network connection is disabled since the NetworkManager DBus connection could not be initialized.\n\n"
 	}
@@ -47,3 +57,38 @@ func main() {
 	agent.RemoveObexAgent()
 	agent.RemoveAgent()
 }
+
+// startDaemon registers the pairing and OBEX agents without the UI,
+// and waits until the daemon is interrupted or terminated.
+func startDaemon(bluezConn *bluez.Bluez) {
+	cmd.Init(bluezConn)
+
+	obexConn, err := bluez.NewObex()
+	if err != nil {
+		cmd.PrintWarn("Receiving files is disabled since the bluez OBEX DBus connection could not be initialized.")
+	}
+
+	handler, err := agent.NewDaemonHandler(bluezConn, obexConn)
+	if err != nil {
+		cmd.PrintError("Could not setup daemon", err)
+	}
+	agent.SetHandler(handler)
+
+	if err := agent.SetupAgent(bluezConn.Conn()); err != nil {
+		cmd.PrintError("Could not setup bluez agent", err)
+	}
+	defer agent.RemoveAgent()
+
+	if obexConn != nil {
+		if err := agent.SetupObexAgent(); err != nil {
+			cmd.PrintWarn("Receiving files is disabled since the bluez OBEX agent could not be setup.")
+		} else {
+			defer agent.RemoveObexAgent()
+		}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	<-signals
+}
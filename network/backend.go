@@ -0,0 +1,49 @@
+package network
+
+import (
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+)
+
+// Backend describes a network backend, which connects to the network of a device.
+type Backend interface {
+	// Connect connects to the device's network with the provided connection type.
+	Connect(name, connType, bdaddr string) error
+
+	// DeactivateConnection cancels the connection attempt to the device's network.
+	DeactivateConnection(bdaddr string) error
+
+	// DisconnectDevice disconnects from the device's network.
+	DisconnectDevice(bdaddr string) error
+
+	// GetConnectionStatus returns the status of the device's network connection.
+	GetConnectionStatus(bdaddr string) (ConnectionStatus, error)
+
+	// WatchState notifies on the returned channel when the connection state changes.
+	WatchState(exit chan struct{}) <-chan struct{}
+}
+
+// NewBackend returns the network backend set with the "network-backend" option.
+// If no backend is set, the NetworkManager backend is used if it is available,
+// otherwise the bluez backend is used.
+func NewBackend(b *bluez.Bluez) (Backend, error) {
+	switch cmd.GetProperty("network-backend") {
+	case "bluez":
+		return NewBluezNetwork(b), nil
+
+	case "networkmanager":
+		network, err := NewNetwork()
+		if err != nil {
+			return nil, err
+		}
+
+		return network, nil
+	}
+
+	network, err := NewNetwork()
+	if err != nil {
+		return NewBluezNetwork(b), nil
+	}
+
+	return network, nil
+}
@@ -0,0 +1,324 @@
+package network
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+)
+
+// BluezNetwork is a network backend which connects to the network
+// of a device via the bluez Network1 interface.
+type BluezNetwork struct {
+	bluez *bluez.Bluez
+
+	interfaces     map[string]string
+	interfacesLock sync.Mutex
+}
+
+const dbusBluezNetworkIface = "org.bluez.Network1"
+
+// NewBluezNetwork returns a new BluezNetwork.
+func NewBluezNetwork(b *bluez.Bluez) *BluezNetwork {
+	n := &BluezNetwork{
+		bluez:      b,
+		interfaces: make(map[string]string),
+	}
+
+	go n.watchDisconnect()
+
+	return n
+}
+
+// Connect connects to the device's network access point. Only the
+// "panu" connection type is supported by this backend.
+func (n *BluezNetwork) Connect(name, connType, bdaddr string) error {
+	if connType != "panu" {
+		return errors.New("Only PANU connections are supported by the bluez network backend")
+	}
+
+	device, ok := n.bluez.GetDeviceFromAddress(bdaddr)
+	if !ok {
+		return errors.New("Device not found")
+	}
+
+	properties, err := n.bluez.GetNetworkProperties(device.Path)
+	if err == nil && properties.Connected {
+		return ConnectionAlreadyActive
+	}
+
+	iface, err := n.bluez.ConnectNetwork(device.Path, "nap")
+	if err != nil {
+		return err
+	}
+
+	n.interfacesLock.Lock()
+	n.interfaces[bdaddr] = iface
+	n.interfacesLock.Unlock()
+
+	return configureInterface(iface, bdaddr, "up")
+}
+
+// DeactivateConnection disconnects from the device's network.
+func (n *BluezNetwork) DeactivateConnection(bdaddr string) error {
+	return n.DisconnectDevice(bdaddr)
+}
+
+// DisconnectDevice disconnects from the device's network.
+func (n *BluezNetwork) DisconnectDevice(bdaddr string) error {
+	device, ok := n.bluez.GetDeviceFromAddress(bdaddr)
+	if !ok {
+		return errors.New("Device not found")
+	}
+
+	n.interfacesLock.Lock()
+	iface := n.interfaces[bdaddr]
+	delete(n.interfaces, bdaddr)
+	n.interfacesLock.Unlock()
+
+	properties, err := n.bluez.GetNetworkProperties(device.Path)
+	if err != nil || !properties.Connected {
+		return err
+	}
+	if properties.Interface != "" {
+		iface = properties.Interface
+	}
+
+	if err := n.bluez.DisconnectNetwork(device.Path); err != nil {
+		return err
+	}
+
+	return configureInterface(iface, bdaddr, "down")
+}
+
+// GetConnectionStatus returns the status of the device's network connection.
+// The DNS servers are not known to this backend, and are not returned.
+func (n *BluezNetwork) GetConnectionStatus(bdaddr string) (ConnectionStatus, error) {
+	var status ConnectionStatus
+
+	device, ok := n.bluez.GetDeviceFromAddress(bdaddr)
+	if !ok {
+		return status, errors.New("Device not found")
+	}
+
+	properties, err := n.bluez.GetNetworkProperties(device.Path)
+	if err != nil || !properties.Connected {
+		return status, err
+	}
+
+	status.Name = device.Name + " (bluez)"
+	status.Type = "panu"
+	status.State = "Activated"
+	status.Active = true
+	status.Interface = properties.Interface
+
+	if iface, err := net.InterfaceByName(properties.Interface); err == nil {
+		addresses, _ := iface.Addrs()
+		for _, address := range addresses {
+			ip, _, err := net.ParseCIDR(address.String())
+			if err != nil {
+				continue
+			}
+
+			if ip.To4() != nil {
+				status.IPv4 = append(status.IPv4, address.String())
+			} else {
+				status.IPv6 = append(status.IPv6, address.String())
+			}
+		}
+	}
+
+	status.Gateway = interfaceGateway(properties.Interface)
+	status.RxBytes = interfaceStatistic(properties.Interface, "rx_bytes")
+	status.TxBytes = interfaceStatistic(properties.Interface, "tx_bytes")
+
+	return status, nil
+}
+
+// WatchState sends a notification on the returned channel whenever the
+// network connection properties of a device change. The watch is stopped
+// once the exit channel is closed.
+func (n *BluezNetwork) WatchState(exit chan struct{}) <-chan struct{} {
+	conn := n.bluez.Conn()
+
+	changed := make(chan struct{}, 1)
+	signals := make(chan *dbus.Signal, 10)
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+		dbus.WithMatchArg(0, dbusBluezNetworkIface),
+	}
+
+	if err := conn.AddMatchSignal(match...); err != nil {
+		return changed
+	}
+	conn.Signal(signals)
+
+	go func() {
+		for {
+			select {
+			case <-exit:
+				removed := make(chan struct{})
+
+				go func() {
+					conn.RemoveSignal(signals)
+					conn.RemoveMatchSignal(match...)
+
+					close(removed)
+				}()
+
+				for {
+					select {
+					case <-signals:
+					case <-removed:
+						return
+					}
+				}
+
+			case signal := <-signals:
+				if signal.Name != "org.freedesktop.DBus.Properties.PropertiesChanged" ||
+					len(signal.Body) == 0 || signal.Body[0] != dbusBluezNetworkIface {
+					continue
+				}
+
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return changed
+}
+
+// watchDisconnect runs the network hook with the "down" event, when
+// the network of a device which was connected from this backend is
+// disconnected by the device.
+func (n *BluezNetwork) watchDisconnect() {
+	conn := n.bluez.Conn()
+	signals := make(chan *dbus.Signal, 10)
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+		dbus.WithMatchArg(0, dbusBluezNetworkIface),
+	); err != nil {
+		return
+	}
+	conn.Signal(signals)
+
+	for signal := range signals {
+		if signal.Name != "org.freedesktop.DBus.Properties.PropertiesChanged" ||
+			len(signal.Body) < 2 || signal.Body[0] != dbusBluezNetworkIface {
+			continue
+		}
+
+		changed, ok := signal.Body[1].(map[string]dbus.Variant)
+		if !ok {
+			continue
+		}
+
+		var connected bool
+		if value, ok := changed["Connected"]; !ok || value.Store(&connected) != nil || connected {
+			continue
+		}
+
+		bdaddr := n.bluez.GetDevice(string(signal.Path)).Address
+
+		n.interfacesLock.Lock()
+		iface, ok := n.interfaces[bdaddr]
+		delete(n.interfaces, bdaddr)
+		n.interfacesLock.Unlock()
+
+		if ok {
+			configureInterface(iface, bdaddr, "down")
+		}
+	}
+}
+
+// configureInterface hands over the network interface to systemd-networkd and
+// the network hook, if they are set. The event is either "up" or "down".
+func configureInterface(iface, bdaddr, event string) error {
+	if iface == "" {
+		return nil
+	}
+
+	if event == "up" && cmd.IsPropertyEnabled("network-networkd") {
+		if out, err := exec.Command("networkctl", "reconfigure", iface).CombinedOutput(); err != nil {
+			return errors.Wrap(errors.New(strings.TrimSpace(string(out))), "Cannot reconfigure "+iface+" via systemd-networkd")
+		}
+	}
+
+	hook := cmd.GetProperty("network-hook")
+	if hook == "" {
+		return nil
+	}
+
+	command := exec.Command("sh", "-c", hook)
+	command.Env = append(os.Environ(),
+		"BLUETUITH_NETWORK_EVENT="+event,
+		"BLUETUITH_NETWORK_INTERFACE="+iface,
+		"BLUETUITH_DEVICE_ADDRESS="+bdaddr,
+	)
+
+	if out, err := command.CombinedOutput(); err != nil {
+		return errors.Wrap(err, "Network hook failed: "+strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// interfaceGateway returns the default IPv4 gateway of the network interface.
+func interfaceGateway(iface string) string {
+	file, err := os.Open("/proc/net/route")
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[0] != iface || fields[1] != "00000000" {
+			continue
+		}
+
+		gateway, err := hex.DecodeString(fields[2])
+		if err != nil || len(gateway) != net.IPv4len {
+			continue
+		}
+
+		// The gateway is stored in host byte order.
+		ip := make(net.IP, net.IPv4len)
+		binary.LittleEndian.PutUint32(ip, binary.BigEndian.Uint32(gateway))
+
+		return ip.String()
+	}
+
+	return ""
+}
+
+// interfaceStatistic returns the value of a statistic of the network interface.
+func interfaceStatistic(iface, statistic string) uint64 {
+	data, err := os.ReadFile(filepath.Join("/sys/class/net", iface, "statistics", statistic))
+	if err != nil {
+		return 0
+	}
+
+	value, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+
+	return value
+}
@@ -0,0 +1,95 @@
+package network
+
+import (
+	"github.com/darkhz/bluetuith/cmd"
+)
+
+// DunSettings holds the dialup network settings of a device.
+type DunSettings struct {
+	APN      string
+	Number   string
+	Username string
+	Password string
+	PIN      string
+}
+
+// dunProperties matches the device configuration properties
+// to the NetworkManager gsm setting keys.
+var dunProperties = [][]string{
+	{"dun-apn", "apn"},
+	{"dun-number", "number"},
+	{"dun-username", "username"},
+	{"dun-password", "password"},
+	{"dun-pin", "pin"},
+}
+
+// HasDunSettings returns whether dialup network settings are stored for the device.
+func HasDunSettings(bdaddr string) bool {
+	return cmd.GetDeviceProperty(bdaddr, "dun-number") != ""
+}
+
+// GetDunSettings returns the dialup network settings of the device. If no settings
+// are stored for the device, the global gsm-apn and gsm-number values are used.
+func GetDunSettings(bdaddr string) DunSettings {
+	if !HasDunSettings(bdaddr) {
+		return DunSettings{
+			APN:    cmd.GetProperty("gsm-apn"),
+			Number: cmd.GetProperty("gsm-number"),
+		}
+	}
+
+	values := make(map[string]string, len(dunProperties))
+	for _, property := range dunProperties {
+		values[property[1]] = cmd.GetDeviceProperty(bdaddr, property[0])
+	}
+
+	return DunSettings{
+		APN:      values["apn"],
+		Number:   values["number"],
+		Username: values["username"],
+		Password: values["password"],
+		PIN:      values["pin"],
+	}
+}
+
+// SaveDunSettings stores the dialup network settings of the device.
+func SaveDunSettings(bdaddr string, settings DunSettings) error {
+	values := settings.values()
+
+	properties := make(map[string]interface{}, len(dunProperties))
+	for _, property := range dunProperties {
+		properties[property[0]] = values[property[1]]
+	}
+
+	return cmd.SaveDeviceProperties(bdaddr, properties)
+}
+
+// apply applies the dialup network settings to the gsm section of a connection.
+func (d DunSettings) apply(gsmSettings map[string]interface{}) {
+	for key, value := range d.values() {
+		if value == "" && key != "apn" && key != "number" {
+			delete(gsmSettings, key)
+			continue
+		}
+
+		gsmSettings[key] = value
+	}
+
+	if d.Password != "" {
+		gsmSettings["password-flags"] = uint32(0)
+	}
+	if d.PIN != "" {
+		gsmSettings["pin-flags"] = uint32(0)
+	}
+}
+
+// values returns the dialup network settings mapped to the gsm setting keys.
+func (d DunSettings) values() map[string]string {
+	return map[string]string{
+		"apn":      d.APN,
+		"number":   d.Number,
+		"username": d.Username,
+		"password": d.Password,
+		"pin":      d.PIN,
+	}
+}
@@ -3,7 +3,9 @@ package network
 import "errors"
 
 var (
-	NMConnectionAlreadyActive = errors.New("Connection is already active")
+	ConnectionAlreadyActive = errors.New("Connection is already active")
+
+	NMConnectionAlreadyActive = ConnectionAlreadyActive
 	NMConnectionError         = errors.New("Connection error occurred")
 
 	NMSettingModifyError = errors.New("Cannot modify connection settings")
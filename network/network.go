@@ -4,7 +4,9 @@ import (
 	"sync"
 
 	nm "github.com/Wifx/gonetworkmanager"
+	"github.com/godbus/dbus/v5"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
 // Network holds the network manager and active connections.
@@ -15,8 +17,24 @@ type Network struct {
 	connectionLock   sync.Mutex
 }
 
-// NewNetwork returns a new Network.
+// NewNetwork returns a new Network. An error is returned
+// if NetworkManager is not running.
 func NewNetwork() (*Network, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	var running bool
+	if err := conn.BusObject().
+		Call("org.freedesktop.DBus.NameHasOwner", 0, nm.NetworkManagerInterface).
+		Store(&running); err != nil {
+		return nil, err
+	}
+	if !running {
+		return nil, errors.New("NetworkManager is not running")
+	}
+
 	manager, err := nm.NewNetworkManager()
 	if err != nil {
 		return nil, err
@@ -37,7 +55,7 @@ func (n *Network) Connect(name, connType, bdaddr string) error {
 		return err
 	}
 	if active {
-		return NMConnectionAlreadyActive
+		return ConnectionAlreadyActive
 	}
 
 	activated, err := n.ActivateExistingConnection(connType, bdaddr)
@@ -111,7 +129,7 @@ func (n *Network) ActivateExistingConnection(connType, bdaddr string) (bool, err
 				return false, err
 			}
 			if exist {
-				err := checkSettings(conn, connType)
+				err := checkSettings(conn, connType, bdaddr)
 				if err != nil {
 					return false, err
 				}
@@ -0,0 +1,167 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	nm "github.com/Wifx/gonetworkmanager"
+)
+
+// Profile describes a NetworkManager bluetooth connection profile.
+type Profile struct {
+	UUID    string
+	Name    string
+	Type    string
+	Address string
+
+	Autoconnect bool
+	IPv4Method  string
+	DNS         []string
+	Metric      int64
+}
+
+// IPv4Methods lists the supported IPv4 configuration methods of a profile.
+var IPv4Methods = []string{"auto", "link-local", "shared", "disabled"}
+
+// GetProfiles returns all connection profiles which have a bluetooth address set.
+func (n *Network) GetProfiles() ([]Profile, error) {
+	var profiles []Profile
+
+	settings, err := nm.NewSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	conns, err := settings.ListConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, conn := range conns {
+		connSettings, err := conn.GetSettings()
+		if err != nil {
+			return nil, err
+		}
+
+		addr, ok := connSettings["bluetooth"]["bdaddr"].([]byte)
+		if !ok {
+			continue
+		}
+
+		profile := Profile{
+			Address:     getMacAddress(addr),
+			Autoconnect: true,
+			IPv4Method:  "auto",
+			Metric:      -1,
+		}
+
+		profile.UUID, _ = connSettings["connection"]["uuid"].(string)
+		profile.Name, _ = connSettings["connection"]["id"].(string)
+		profile.Type, _ = connSettings["bluetooth"]["type"].(string)
+
+		if autoconnect, ok := connSettings["connection"]["autoconnect"].(bool); ok {
+			profile.Autoconnect = autoconnect
+		}
+		if method, ok := connSettings["ipv4"]["method"].(string); ok {
+			profile.IPv4Method = method
+		}
+		if metric, ok := connSettings["ipv4"]["route-metric"].(int64); ok {
+			profile.Metric = metric
+		}
+		if dns, ok := connSettings["ipv4"]["dns"].([]uint32); ok {
+			for _, address := range dns {
+				ip := make(net.IP, net.IPv4len)
+				binary.LittleEndian.PutUint32(ip, address)
+
+				profile.DNS = append(profile.DNS, ip.String())
+			}
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles, nil
+}
+
+// UpdateProfile updates the autoconnect, IPv4 method, DNS and
+// route metric settings of the connection profile.
+func (n *Network) UpdateProfile(profile Profile) error {
+	settings, err := nm.NewSettings()
+	if err != nil {
+		return err
+	}
+
+	conn, err := settings.GetConnectionByUUID(profile.UUID)
+	if err != nil {
+		return err
+	}
+
+	connSettings, err := conn.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	var dns []uint32
+	for _, address := range profile.DNS {
+		ip := net.ParseIP(strings.TrimSpace(address)).To4()
+		if ip == nil {
+			return fmt.Errorf("Invalid DNS address '%s'", address)
+		}
+
+		// The addresses are stored in network byte order.
+		dns = append(dns, binary.LittleEndian.Uint32(ip))
+	}
+
+	if _, ok := connSettings["ipv4"]; !ok {
+		connSettings["ipv4"] = make(map[string]interface{})
+	}
+
+	connSettings["connection"]["autoconnect"] = profile.Autoconnect
+	connSettings["ipv4"]["method"] = profile.IPv4Method
+	connSettings["ipv4"]["dns"] = dns
+	connSettings["ipv4"]["ignore-auto-dns"] = dns != nil
+	connSettings["ipv4"]["route-metric"] = profile.Metric
+
+	// The deprecated address and route properties are returned alongside
+	// their replacements, and are rejected by NetworkManager when updating.
+	for _, section := range []string{"ipv4", "ipv6"} {
+		delete(connSettings[section], "addresses")
+		delete(connSettings[section], "routes")
+	}
+
+	// Secrets are not returned with the settings, and have to be set again.
+	if gsmSettings, ok := connSettings["gsm"]; ok {
+		secrets, err := conn.GetSecrets("gsm")
+		if err != nil {
+			return err
+		}
+
+		for key, value := range secrets["gsm"] {
+			gsmSettings[key] = value
+		}
+	}
+
+	return conn.Update(connSettings)
+}
+
+// DeleteProfile deletes the connection profile.
+func (n *Network) DeleteProfile(uuid string) error {
+	settings, err := nm.NewSettings()
+	if err != nil {
+		return err
+	}
+
+	conn, err := settings.GetConnectionByUUID(uuid)
+	if err != nil {
+		return err
+	}
+
+	return conn.Delete()
+}
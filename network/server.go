@@ -0,0 +1,205 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"unsafe"
+
+	nm "github.com/Wifx/gonetworkmanager"
+	"github.com/google/uuid"
+)
+
+// BridgeClient holds the address of a client connected to the NAP server,
+// and the bnep interface of the client which is attached to the bridge.
+type BridgeClient struct {
+	Address   string
+	Interface string
+}
+
+// bnepConnInfo describes the kernel's bnep_conninfo structure.
+type bnepConnInfo struct {
+	flags  uint32
+	role   uint16
+	state  uint16
+	dst    [6]byte
+	device [16]byte
+	_      [2]byte
+}
+
+// bnepConnListReq describes the kernel's bnep_connlist_req structure.
+type bnepConnListReq struct {
+	cnum uint32
+	ci   unsafe.Pointer
+}
+
+const (
+	// btProtoBnep is the bluetooth socket protocol of BNEP.
+	btProtoBnep = 4
+
+	// bnepGetConnList is the ioctl request to list the BNEP connections.
+	bnepGetConnList = 0x800442d2
+
+	// bnepMaxConnections is the maximum number of BNEP connections to list.
+	bnepMaxConnections = 32
+)
+
+// StartSharedBridge activates a NetworkManager bridge connection for the
+// provided interface, which shares the host's internet connection with
+// the clients connected to the bridge. The connection is created if it
+// does not exist.
+func (n *Network) StartSharedBridge(bridge string) error {
+	conn, err := findBridgeConnection(bridge)
+	if err != nil {
+		return err
+	}
+
+	if conn == nil {
+		newUUID, err := uuid.NewUUID()
+		if err != nil {
+			return err
+		}
+
+		settings, err := nm.NewSettings()
+		if err != nil {
+			return err
+		}
+
+		conn, err = settings.AddConnection(getBridgeSettings(bridge, newUUID.String()))
+		if err != nil {
+			return err
+		}
+	}
+
+	activeConn, err := n.Manager.ActivateConnection(conn, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	n.connectionLock.Lock()
+	n.ActiveConnection[bridge] = activeConn
+	n.connectionLock.Unlock()
+
+	return nil
+}
+
+// StopSharedBridge deactivates the shared bridge connection.
+func (n *Network) StopSharedBridge(bridge string) error {
+	return n.DeactivateConnection(bridge)
+}
+
+// BridgeInterfaces returns the network interfaces which are attached to the bridge.
+func BridgeInterfaces(bridge string) []string {
+	entries, err := os.ReadDir(filepath.Join("/sys/class/net", bridge, "brif"))
+	if err != nil {
+		return nil
+	}
+
+	interfaces := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		interfaces = append(interfaces, entry.Name())
+	}
+	sort.Strings(interfaces)
+
+	return interfaces
+}
+
+// BridgeClients returns the clients whose bnep interfaces are attached to the bridge.
+// The client addresses are looked up from the kernel's list of BNEP connections.
+func BridgeClients(bridge string) ([]BridgeClient, error) {
+	var clients []BridgeClient
+
+	interfaces := make(map[string]struct{})
+	for _, iface := range BridgeInterfaces(bridge) {
+		interfaces[iface] = struct{}{}
+	}
+	if len(interfaces) == 0 {
+		return nil, nil
+	}
+
+	fd, err := syscall.Socket(syscall.AF_BLUETOOTH, syscall.SOCK_RAW, btProtoBnep)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+
+	conns := make([]bnepConnInfo, bnepMaxConnections)
+	req := &bnepConnListReq{
+		cnum: bnepMaxConnections,
+		ci:   unsafe.Pointer(&conns[0]),
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), bnepGetConnList, uintptr(unsafe.Pointer(req)))
+	if errno != 0 {
+		return nil, errno
+	}
+
+	for _, conn := range conns[:req.cnum] {
+		device := string(conn.device[:bytes.IndexByte(append(conn.device[:], 0), 0)])
+		if _, ok := interfaces[device]; !ok {
+			continue
+		}
+
+		clients = append(clients, BridgeClient{
+			Address: fmt.Sprintf(
+				"%02X:%02X:%02X:%02X:%02X:%02X",
+				conn.dst[0], conn.dst[1], conn.dst[2], conn.dst[3], conn.dst[4], conn.dst[5],
+			),
+			Interface: device,
+		})
+	}
+
+	return clients, nil
+}
+
+// findBridgeConnection returns the bridge connection profile for the interface.
+func findBridgeConnection(bridge string) (nm.Connection, error) {
+	settings, err := nm.NewSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	conns, err := settings.ListConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, conn := range conns {
+		connSettings, err := conn.GetSettings()
+		if err != nil {
+			return nil, err
+		}
+
+		if connSettings["connection"]["type"] == "bridge" &&
+			connSettings["connection"]["interface-name"] == bridge {
+			return conn, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getBridgeSettings returns a shared bridge connection setting.
+func getBridgeSettings(bridge, uuid string) map[string]map[string]interface{} {
+	return map[string]map[string]interface{}{
+		"connection": {
+			"id":             "Bluetooth NAP (" + bridge + ")",
+			"type":           "bridge",
+			"uuid":           uuid,
+			"interface-name": bridge,
+			"autoconnect":    false,
+		},
+		"bridge": {
+			"stp": false,
+		},
+		"ipv4": {
+			"method": "shared",
+		},
+		"ipv6": {
+			"method": "ignore",
+		},
+	}
+}
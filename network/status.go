@@ -0,0 +1,236 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+
+	nm "github.com/Wifx/gonetworkmanager"
+	"github.com/godbus/dbus/v5"
+)
+
+// ConnectionStatus holds the status of a device's network connection.
+type ConnectionStatus struct {
+	Name      string
+	Type      string
+	State     string
+	Active    bool
+	Interface string
+
+	IPv4, IPv6 []string
+	Gateway    string
+	DNS        []string
+
+	RxBytes, TxBytes uint64
+}
+
+// statisticsRefreshRate is the refresh rate of the device statistics, in milliseconds.
+const statisticsRefreshRate = 1000
+
+// connectionTypes lists the bluetooth connection types.
+var connectionTypes = []string{"panu", "dun"}
+
+// GetConnectionStatus returns the status of the device's active network connection.
+// If the device has no active connection, a status with an empty state is returned.
+func (n *Network) GetConnectionStatus(bdaddr string) (ConnectionStatus, error) {
+	var status ConnectionStatus
+
+	activeConn, connType, err := n.findActiveConnection(bdaddr)
+	if err != nil || activeConn == nil {
+		return status, err
+	}
+
+	status.Type = connType
+
+	if status.Name, err = activeConn.GetPropertyID(); err != nil {
+		return status, err
+	}
+
+	state, err := activeConn.GetPropertyState()
+	if err != nil {
+		return status, err
+	}
+	status.Active = state == nm.NmActiveConnectionStateActivated
+	status.State = strings.TrimPrefix(state.String(), "NmActiveConnectionState")
+
+	devices, err := activeConn.GetPropertyDevices()
+	if err != nil {
+		return status, err
+	}
+	if len(devices) > 0 {
+		status.Interface, _ = devices[0].GetPropertyIpInterface()
+		status.RxBytes, status.TxBytes = getDeviceStatistics(devices[0].GetPath())
+	}
+
+	if ip4Config, err := activeConn.GetPropertyIP4Config(); err == nil && ip4Config != nil {
+		addresses, _ := ip4Config.GetPropertyAddressData()
+		for _, address := range addresses {
+			status.IPv4 = append(status.IPv4, fmt.Sprintf("%s/%d", address.Address, address.Prefix))
+		}
+
+		nameservers, _ := ip4Config.GetPropertyNameserverData()
+		for _, nameserver := range nameservers {
+			status.DNS = append(status.DNS, nameserver.Address)
+		}
+
+		status.Gateway, _ = ip4Config.GetPropertyGateway()
+	}
+
+	if ip6Config, err := activeConn.GetPropertyIP6Config(); err == nil && ip6Config != nil {
+		addresses, _ := ip6Config.GetPropertyAddressData()
+		for _, address := range addresses {
+			status.IPv6 = append(status.IPv6, fmt.Sprintf("%s/%d", address.Address, address.Prefix))
+		}
+
+		if status.Gateway == "" {
+			status.Gateway, _ = ip6Config.GetPropertyGateway()
+		}
+	}
+
+	return status, nil
+}
+
+// DisconnectDevice deactivates the device's active network connection.
+func (n *Network) DisconnectDevice(bdaddr string) error {
+	n.connectionLock.Lock()
+	delete(n.ActiveConnection, bdaddr)
+	n.connectionLock.Unlock()
+
+	activeConn, _, err := n.findActiveConnection(bdaddr)
+	if err != nil || activeConn == nil {
+		return err
+	}
+
+	return n.Manager.DeactivateConnection(activeConn)
+}
+
+// WatchState sends a notification on the returned channel whenever the state
+// of a NetworkManager connection or device changes. The watch is stopped
+// once the exit channel is closed.
+func (n *Network) WatchState(exit chan struct{}) <-chan struct{} {
+	changed := make(chan struct{}, 1)
+	signals := make(chan *dbus.Signal, 10)
+
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return changed
+	}
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchSender(nm.NetworkManagerInterface),
+		dbus.WithMatchPathNamespace(nm.NetworkManagerObjectPath),
+	}
+
+	if err := conn.AddMatchSignal(match...); err != nil {
+		return changed
+	}
+	conn.Signal(signals)
+
+	go func() {
+		for {
+			select {
+			case <-exit:
+				removed := make(chan struct{})
+
+				go func() {
+					conn.RemoveSignal(signals)
+					conn.RemoveMatchSignal(match...)
+
+					close(removed)
+				}()
+
+				for {
+					select {
+					case <-signals:
+					case <-removed:
+						return
+					}
+				}
+
+			case signal := <-signals:
+				if !isStateSignal(signal) {
+					continue
+				}
+
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return changed
+}
+
+// findActiveConnection returns the device's active network connection and its type.
+func (n *Network) findActiveConnection(bdaddr string) (nm.ActiveConnection, string, error) {
+	activeConnections, err := n.Manager.GetPropertyActiveConnections()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, activeConn := range activeConnections {
+		ctype, err := activeConn.GetPropertyType()
+		if err != nil {
+			return nil, "", err
+		}
+		if ctype != "bluetooth" {
+			continue
+		}
+
+		conn, err := activeConn.GetPropertyConnection()
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, connType := range connectionTypes {
+			exist, err := isDeviceAddrExist(conn, connType, bdaddr)
+			if err != nil {
+				return nil, "", err
+			}
+			if exist {
+				return activeConn, connType, nil
+			}
+		}
+	}
+
+	return nil, "", nil
+}
+
+// getDeviceStatistics returns the received and transmitted byte counters of the device.
+func getDeviceStatistics(devicePath dbus.ObjectPath) (uint64, uint64) {
+	statistics, err := nm.NewDeviceStatistics(devicePath)
+	if err != nil {
+		return 0, 0
+	}
+
+	if rate, err := statistics.GetPropertyRefreshRateMs(); err == nil && rate == 0 {
+		statistics.SetPropertyRefreshRateMs(statisticsRefreshRate)
+	}
+
+	rx, _ := statistics.GetPropertyRxBytes()
+	tx, _ := statistics.GetPropertyTxBytes()
+
+	return rx, tx
+}
+
+// isStateSignal returns whether the signal is a NetworkManager state change signal.
+func isStateSignal(signal *dbus.Signal) bool {
+	switch signal.Name {
+	case nm.ActiveConnectionInterface + "." + nm.ActiveConnectionSignalStateChanged,
+		nm.DeviceInterface + ".StateChanged":
+		return true
+
+	case "org.freedesktop.DBus.Properties.PropertiesChanged":
+		if len(signal.Body) == 0 {
+			return false
+		}
+
+		iface, ok := signal.Body[0].(string)
+
+		return ok && (iface == nm.ActiveConnectionInterface ||
+			iface == nm.IP4ConfigInterface || iface == nm.IP6ConfigInterface)
+	}
+
+	return false
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	nm "github.com/Wifx/gonetworkmanager"
-	"github.com/darkhz/bluetuith/cmd"
 )
 
 // isDeviceAddrExist checks if the device's address is present
@@ -30,7 +29,7 @@ func isDeviceAddrExist(conn nm.Connection, connType, bdaddr string) (bool, error
 }
 
 // checkSettings checks and modifies the device connection's settings.
-func checkSettings(conn nm.Connection, connType string) error {
+func checkSettings(conn nm.Connection, connType, bdaddr string) error {
 	if connType != "dun" {
 		return nil
 	}
@@ -45,16 +44,7 @@ func checkSettings(conn nm.Connection, connType string) error {
 		return NMSettingModifyError
 	}
 
-	apn := cmd.GetProperty("gsm-apn")
-	number := cmd.GetProperty("gsm-number")
-
-	if apn != gsmSettings["apn"] {
-		gsmSettings["apn"] = apn
-	}
-
-	if number != gsmSettings["number"] {
-		gsmSettings["number"] = number
-	}
+	GetDunSettings(bdaddr).apply(gsmSettings)
 
 	delete(settings, "ipv6")
 
@@ -81,13 +71,8 @@ func getSettings(bdaddr []byte, name, connType, uuid string) map[string]map[stri
 	}
 
 	if connType == "dun" {
-		apn := cmd.GetProperty("gsm-apn")
-		number := cmd.GetProperty("gsm-number")
-
-		settings["gsm"] = map[string]interface{}{
-			"apn":    apn,
-			"number": number,
-		}
+		settings["gsm"] = make(map[string]interface{})
+		GetDunSettings(getMacAddress(bdaddr)).apply(settings["gsm"])
 	}
 
 	return settings
@@ -0,0 +1,207 @@
+package notify
+
+import (
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+)
+
+const (
+	dbusNotifyName  = "org.freedesktop.Notifications"
+	dbusNotifyIface = "org.freedesktop.Notifications"
+	dbusNotifyPath  = dbus.ObjectPath("/org/freedesktop/Notifications")
+)
+
+// Urgency describes the urgency level of a notification.
+type Urgency byte
+
+// The different urgency levels of a notification.
+const (
+	UrgencyLow Urgency = iota
+	UrgencyNormal
+	UrgencyCritical
+)
+
+// Action describes a notification action.
+type Action struct {
+	Key, Label string
+}
+
+// Notifier stores the session bus connection, and the reply
+// channels of notifications which have actions.
+type Notifier struct {
+	conn    *dbus.Conn
+	replies map[uint32]chan string
+
+	watch sync.Once
+	lock  sync.Mutex
+}
+
+var notifier Notifier
+
+// Send sends a desktop notification via the session bus,
+// and returns the ID of the notification.
+func Send(summary, body string, urgency Urgency) (uint32, error) {
+	notifier.lock.Lock()
+	defer notifier.lock.Unlock()
+
+	return notify(summary, body, urgency)
+}
+
+// SendWithActions sends a desktop notification with the provided actions.
+// The returned channel receives the key of the invoked action, or an empty
+// string if the notification was closed without invoking an action.
+func SendWithActions(summary, body string, urgency Urgency, actions ...Action) (uint32, chan string, error) {
+	notifier.lock.Lock()
+	defer notifier.lock.Unlock()
+
+	if err := watchNotifications(); err != nil {
+		return 0, nil, err
+	}
+
+	id, err := notify(summary, body, urgency, actions...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	reply := make(chan string, 1)
+	notifier.replies[id] = reply
+
+	return id, reply, nil
+}
+
+// Close closes the notification with the provided ID.
+func Close(id uint32) error {
+	notifier.lock.Lock()
+	defer notifier.lock.Unlock()
+
+	conn, err := sessionBus()
+	if err != nil {
+		return err
+	}
+
+	return conn.Object(dbusNotifyName, dbusNotifyPath).
+		Call(dbusNotifyIface+".CloseNotification", 0, id).
+		Store()
+}
+
+// notify sends a desktop notification.
+func notify(summary, body string, urgency Urgency, actions ...Action) (uint32, error) {
+	var id uint32
+
+	conn, err := sessionBus()
+	if err != nil {
+		return 0, err
+	}
+
+	actionList := []string{}
+	for _, action := range actions {
+		actionList = append(actionList, action.Key, action.Label)
+	}
+
+	hints := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(byte(urgency)),
+	}
+
+	err = conn.Object(dbusNotifyName, dbusNotifyPath).
+		Call(dbusNotifyIface+".Notify", 0,
+			"bluetuith", uint32(0), "bluetooth",
+			summary, body, actionList, hints, int32(-1),
+		).Store(&id)
+	if err != nil {
+		return 0, errors.Wrap(err, "Cannot send notification")
+	}
+
+	return id, nil
+}
+
+// watchNotifications listens for notification action and close signals,
+// and passes the replies to the corresponding notification.
+func watchNotifications() error {
+	var err error
+
+	notifier.watch.Do(func() {
+		var conn *dbus.Conn
+
+		conn, err = sessionBus()
+		if err != nil {
+			return
+		}
+
+		err = conn.AddMatchSignal(
+			dbus.WithMatchInterface(dbusNotifyIface),
+			dbus.WithMatchObjectPath(dbusNotifyPath),
+		)
+		if err != nil {
+			return
+		}
+
+		notifier.replies = make(map[uint32]chan string)
+
+		signals := make(chan *dbus.Signal, 10)
+		conn.Signal(signals)
+
+		go func() {
+			for signal := range signals {
+				handleSignal(signal)
+			}
+		}()
+	})
+
+	if err == nil && notifier.replies == nil {
+		err = errors.New("Cannot listen for notification replies")
+	}
+
+	return err
+}
+
+// handleSignal sends the reply of a notification to its reply channel.
+func handleSignal(signal *dbus.Signal) {
+	var action string
+
+	if len(signal.Body) < 2 {
+		return
+	}
+
+	id, ok := signal.Body[0].(uint32)
+	if !ok {
+		return
+	}
+
+	switch signal.Name {
+	case dbusNotifyIface + ".ActionInvoked":
+		action, _ = signal.Body[1].(string)
+
+	case dbusNotifyIface + ".NotificationClosed":
+
+	default:
+		return
+	}
+
+	notifier.lock.Lock()
+	defer notifier.lock.Unlock()
+
+	reply, ok := notifier.replies[id]
+	if !ok {
+		return
+	}
+	delete(notifier.replies, id)
+
+	reply <- action
+}
+
+// sessionBus returns the connection to the session bus.
+func sessionBus() (*dbus.Conn, error) {
+	if notifier.conn != nil {
+		return notifier.conn, nil
+	}
+
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot connect to the session bus")
+	}
+	notifier.conn = conn
+
+	return conn, nil
+}
@@ -3,8 +3,10 @@ package ui
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/darkhz/bluetuith/bluez"
 	"github.com/darkhz/bluetuith/cmd"
@@ -12,6 +14,7 @@ import (
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
 )
 
 // AdapterStatus describes the adapter status display.
@@ -103,6 +106,170 @@ func adapterChange() {
 		})
 }
 
+// getAdapterInfo shows information about the current adapter.
+func getAdapterInfo() {
+	adapter := UI.Bluez.GetCurrentAdapter()
+
+	info, err := UI.Bluez.GetAdapterInfo(adapter.Path)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	props := [][]string{
+		{"Name", info.Name},
+		{"Alias", info.Alias},
+		{"Address", info.Address},
+		{"Class", bluez.FormatDeviceClass(info.Class)},
+		{"Modalias", info.Modalias},
+		{"Roles", strings.Join(info.Roles, ", ")},
+		{"Manufacturer", info.ManufacturerName()},
+		{"Version", info.VersionName()},
+		{"DiscoverableTimeout", adapterTimeout(info.DiscoverableTimeout)},
+		{"PairableTimeout", adapterTimeout(info.PairableTimeout)},
+	}
+	if instances, err := UI.Bluez.GetAdvertisingInstances(adapter.Path); err == nil {
+		props = append(props, []string{
+			"Advertising", fmt.Sprintf("%d/%d instances active", instances.Active, instances.Supported),
+		})
+	}
+	for i, feature := range info.ExperimentalFeatures {
+		name := ""
+		if i == 0 {
+			name = "ExperimentalFeatures"
+		}
+
+		props = append(props, []string{name, feature})
+	}
+	props = append(props, []string{"UUIDs", ""})
+
+	infoModal := NewModal("adapterinfo", "Adapter Information", nil, 40, 100)
+	infoModal.Table.SetSelectionChangedFunc(func(row, col int) {
+		_, _, _, height := infoModal.Table.GetRect()
+		infoModal.Table.SetOffset(row-((height-1)/2), 0)
+	})
+	infoModal.Table.SetSelectedFunc(func(row, col int) {
+		cell := infoModal.Table.GetCell(row, 0)
+		if cell == nil {
+			return
+		}
+
+		property, ok := cell.GetReference().(string)
+		if !ok {
+			return
+		}
+
+		go setAdapterTimeout(infoModal.Table, row, adapter, property)
+	})
+
+	row := 0
+	for _, prop := range props {
+		propName := prop[0]
+		propValue := prop[1]
+
+		if propValue == "" && propName != "UUIDs" {
+			continue
+		}
+
+		if propName != "" {
+			propName = "[::b]" + propName + ":"
+		}
+
+		nameCell := tview.NewTableCell(propName).
+			SetExpansion(1).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Bold(true).
+				Underline(true),
+			)
+		if prop[0] == "DiscoverableTimeout" || prop[0] == "PairableTimeout" {
+			nameCell.SetReference(prop[0])
+		}
+
+		infoModal.Table.SetCell(row, 0, nameCell)
+		infoModal.Table.SetCell(row, 1, tview.NewTableCell(propValue).
+			SetExpansion(1).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)),
+		)
+
+		row++
+	}
+
+	rows := infoModal.Table.GetRowCount() - 1
+	for i, serviceUUID := range info.UUIDs {
+		serviceType := bluez.ServiceType(serviceUUID)
+		serviceUUID = "(" + serviceUUID + ")"
+
+		infoModal.Table.SetCell(rows+i, 1, tview.NewTableCell(serviceType).
+			SetExpansion(1).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)),
+		)
+
+		infoModal.Table.SetCell(rows+i, 2, tview.NewTableCell(serviceUUID).
+			SetExpansion(0).
+			SetTextColor(theme.GetColor(theme.ThemeText)),
+		)
+	}
+
+	infoModal.Height = infoModal.Table.GetRowCount() + 4
+	if infoModal.Height > 60 {
+		infoModal.Height = 60
+	}
+
+	infoModal.Show()
+}
+
+// setAdapterTimeout prompts for and sets the discoverable or pairable
+// timeout of the adapter, and updates the adapter information modal.
+func setAdapterTimeout(infoTable *tview.Table, row int, adapter bluez.Adapter, property string) {
+	input := SetInput(property+" (seconds, 0 to disable):", struct{}{})
+	if input == "" {
+		return
+	}
+
+	timeout, err := strconv.ParseUint(input, 10, 32)
+	if err != nil {
+		ErrorMessage(errors.New("Invalid timeout value"))
+		return
+	}
+
+	if err := UI.Bluez.SetAdapterProperty(adapter.Path, property, uint32(timeout)); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot set "+property))
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		infoTable.GetCell(row, 1).SetText(adapterTimeout(uint32(timeout)))
+	})
+
+	InfoMessage(property+" set to "+adapterTimeout(uint32(timeout)), false)
+}
+
+// adapterTimeout returns the description of an adapter timeout.
+func adapterTimeout(timeout uint32) string {
+	if timeout == 0 {
+		return "0 (disabled)"
+	}
+
+	return (time.Duration(timeout) * time.Second).String()
+}
+
+// setAdapterHeader sets the adapter's alias and ID as the menu bar header.
+func setAdapterHeader(adapter bluez.Adapter) {
+	name := adapter.Alias
+	if name == "" {
+		name = adapter.Name
+	}
+
+	headerText := fmt.Sprintf("[\"adapterchange\"]%s (%s)[\"\"]",
+		name, bluez.GetAdapterID(adapter.Path),
+	)
+	setMenuBarHeader(theme.ColorWrap(theme.ThemeAdapter, headerText, "::bu"))
+}
+
 // updateAdapterStatus updates the adapter status display.
 func updateAdapterStatus(adapter bluez.Adapter) {
 	var state string
@@ -237,11 +404,22 @@ func adapterEvent(signal *dbus.Signal, signalData interface{}) {
 			return
 		}
 
+		UI.QueueUpdateDraw(func() {
+			updateAdapterTableHeader(adapter)
+		})
+
+		if powered, ok := signalPropertyChanged(signal, "org.bluez.Adapter1", "Powered"); ok {
+			if enabled, ok := powered.Value().(bool); ok && enabled {
+				go reconnectAdapterEvent(adapter)
+			}
+		}
+
 		if adapter.Path != UI.Bluez.GetCurrentAdapter().Path {
 			return
 		}
 
 		UI.QueueUpdateDraw(func() {
+			setAdapterHeader(adapter)
 			updateAdapterStatus(adapter)
 		})
 
@@ -264,6 +442,20 @@ func adapterEvent(signal *dbus.Signal, signalData interface{}) {
 				modal.Exit(false)
 				adapterChange()
 			}
+
+			if !isMultiAdapterView() {
+				return
+			}
+
+			switch data := signalData.(type) {
+			case []bluez.Adapter:
+				refreshDeviceTable()
+
+			case string:
+				if _, ok := checkAdapterTable(data); ok {
+					refreshDeviceTable()
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/godbus/dbus/v5"
+)
+
+// AdvertisementInspector describes the advertisement inspector of a device.
+type AdvertisementInspector struct {
+	modal      *Modal
+	devicePath string
+}
+
+var advertisementInspector AdvertisementInspector
+
+// showAdvertisement shows the raw and decoded advertising data
+// of the selected device, which is updated as the data changes.
+func showAdvertisement() {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	modal := NewModal("advertisement", "Advertisement Data", nil, 40, 120)
+	modal.Table.SetSelectionChangedFunc(func(row, col int) {
+		_, _, _, height := modal.Table.GetRect()
+		modal.Table.SetOffset(row-((height-1)/2), 0)
+	})
+
+	advertisementInspector.modal = modal
+	advertisementInspector.devicePath = device.Path
+
+	setAdvertisementTable(device)
+
+	modal.Show()
+}
+
+// setAdvertisementTable writes the advertising data of the device
+// into the advertisement inspector.
+func setAdvertisementTable(device bluez.Device) {
+	table := advertisementInspector.modal.Table
+	row, _ := table.GetSelection()
+
+	table.Clear()
+
+	setCell := func(row, col int, text string, expansion int) {
+		if col == 0 {
+			text = "[::b]" + text
+		}
+
+		table.SetCell(row, col, tview.NewTableCell(text).
+			SetExpansion(expansion).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Bold(true).
+				Underline(true),
+			),
+		)
+	}
+
+	setCell(0, 0, "Device:", 0)
+	setCell(0, 1, device.Name+" ("+device.Address+")", 1)
+	setCell(1, 0, "RSSI:", 0)
+	setCell(1, 1, strconv.FormatInt(int64(device.RSSI), 10)+" dBm", 1)
+
+	fields := device.Advertisement.Fields()
+	if fields == nil {
+		setCell(3, 0, "No advertising data", 0)
+	} else {
+		for col, header := range []string{"Type", "Name", "Raw", "Decoded"} {
+			setCell(3, col, header, 0)
+		}
+	}
+
+	for i, field := range fields {
+		setCell(i+4, 0, field.Type, 0)
+		setCell(i+4, 1, tview.Escape(field.Name), 0)
+		setCell(i+4, 2, field.Raw, 1)
+		setCell(i+4, 3, tview.Escape(field.Decoded), 1)
+	}
+
+	if row >= table.GetRowCount() {
+		row = table.GetRowCount() - 1
+	}
+	table.Select(row, 0)
+}
+
+// advertisementEvent updates the advertisement inspector if
+// the advertising data of the inspected device changes.
+func advertisementEvent(signal *dbus.Signal, signalData interface{}) {
+	device, ok := signalData.(bluez.Device)
+	if !ok {
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		if advertisementInspector.modal == nil || !advertisementInspector.modal.Open ||
+			advertisementInspector.devicePath != device.Path {
+			return
+		}
+
+		setAdvertisementTable(device)
+	})
+}
@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/tview"
+	"github.com/pkg/errors"
+)
+
+// The different advertisement presets.
+const (
+	advertisePresetCustom = iota
+	advertisePresetIBeacon
+	advertisePresetEddystoneURL
+)
+
+var (
+	advertisePresets = []string{"Custom", "iBeacon", "Eddystone-URL"}
+	advertiseTypes   = []string{bluez.AdvertisementPeripheral, bluez.AdvertisementBroadcast}
+)
+
+// toggleAdvertising stops advertising from the current adapter if it is
+// advertising, otherwise it shows a dialog to start advertising.
+func toggleAdvertising() bool {
+	adapter := UI.Bluez.GetCurrentAdapter()
+
+	if !UI.Bluez.IsAdvertising(adapter.Path) {
+		UI.QueueUpdateDraw(func() {
+			advertiseOptions(adapter)
+		})
+
+		return true
+	}
+
+	if err := UI.Bluez.StopAdvertising(adapter.Path); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot stop advertising"))
+		return false
+	}
+
+	setMenuItemToggle("adapter", cmd.KeyAdapterToggleAdvertise, false)
+	InfoMessage("Stopped advertising from "+bluez.GetAdapterID(adapter.Path), false)
+
+	return true
+}
+
+// advertiseOptions shows a dialog to set the advertisement data,
+// and starts advertising from the adapter.
+func advertiseOptions(adapter bluez.Adapter) {
+	title := "Advertise"
+	if instances, err := UI.Bluez.GetAdvertisingInstances(adapter.Path); err == nil {
+		title += fmt.Sprintf(" (%d/%d instances active)", instances.Active, instances.Supported)
+	}
+
+	inputField := func(label, value string, width int, accept func(string, rune) bool) *tview.InputField {
+		return tview.NewInputField().
+			SetLabel(label).
+			SetText(value).
+			SetFieldWidth(width).
+			SetAcceptanceFunc(accept)
+	}
+
+	presetField := tview.NewDropDown().SetLabel("Preset").SetOptions(advertisePresets, nil).SetCurrentOption(0)
+	typeField := tview.NewDropDown().SetLabel("Type").SetOptions(advertiseTypes, nil).SetCurrentOption(0)
+	nameField := inputField("Local Name", "", 30, nil)
+	uuidsField := inputField("Service UUIDs", "", 40, nil)
+	manufacturerField := inputField("Manufacturer Data (id:hex)", "", 40, nil)
+	serviceDataField := inputField("Service Data (uuid:hex)", "", 40, nil)
+	txPowerField := tview.NewCheckbox().SetLabel("Include TxPower").SetChecked(false)
+	timeoutField := inputField("Timeout (seconds)", "", 10, tview.InputFieldInteger)
+	beaconUUIDField := inputField("iBeacon UUID", "", 40, nil)
+	majorField := inputField("iBeacon Major", "0", 10, tview.InputFieldInteger)
+	minorField := inputField("iBeacon Minor", "0", 10, tview.InputFieldInteger)
+	urlField := inputField("Eddystone URL", "", 40, nil)
+	beaconPowerField := inputField("Beacon TxPower (dBm)", "-59", 10, tview.InputFieldInteger)
+
+	form := tview.NewForm()
+	for _, item := range []tview.FormItem{
+		presetField, typeField, nameField, uuidsField,
+		manufacturerField, serviceDataField, txPowerField, timeoutField,
+		beaconUUIDField, majorField, minorField, urlField, beaconPowerField,
+	} {
+		form.AddFormItem(item)
+	}
+
+	modal := NewFormModal("advertise", title, form, 33, 80)
+
+	form.AddButton("Start", func() {
+		text := func(field *tview.InputField) string {
+			return strings.TrimSpace(field.GetText())
+		}
+
+		preset, _ := presetField.GetCurrentOption()
+		_, advertiseType := typeField.GetCurrentOption()
+
+		var err error
+		var options bluez.AdvertisementOptions
+
+		switch preset {
+		case advertisePresetIBeacon:
+			var major, minor uint64
+			var power int64
+
+			if major, err = strconv.ParseUint(text(majorField), 10, 16); err != nil {
+				ErrorMessage(errors.New("Invalid iBeacon major value"))
+				return
+			}
+			if minor, err = strconv.ParseUint(text(minorField), 10, 16); err != nil {
+				ErrorMessage(errors.New("Invalid iBeacon minor value"))
+				return
+			}
+			if power, err = strconv.ParseInt(text(beaconPowerField), 10, 8); err != nil {
+				ErrorMessage(errors.New("Invalid beacon TxPower value"))
+				return
+			}
+
+			options, err = bluez.IBeaconAdvertisement(text(beaconUUIDField), uint16(major), uint16(minor), int8(power))
+
+		case advertisePresetEddystoneURL:
+			var power int64
+
+			if power, err = strconv.ParseInt(text(beaconPowerField), 10, 8); err != nil {
+				ErrorMessage(errors.New("Invalid beacon TxPower value"))
+				return
+			}
+
+			options, err = bluez.EddystoneURLAdvertisement(text(urlField), int8(power))
+
+		default:
+			options.Type = advertiseType
+			options.LocalName = text(nameField)
+
+			for _, uuid := range strings.Split(text(uuidsField), ",") {
+				if uuid = strings.TrimSpace(uuid); uuid != "" {
+					options.ServiceUUIDs = append(options.ServiceUUIDs, uuid)
+				}
+			}
+
+			options.ManufacturerData, err = parseManufacturerData(text(manufacturerField))
+			if err != nil {
+				break
+			}
+
+			options.ServiceData, err = parseAdvertisingData(text(serviceDataField))
+		}
+		if err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		options.IncludeTxPower = txPowerField.IsChecked()
+		if timeout := text(timeoutField); timeout != "" {
+			value, err := strconv.ParseUint(timeout, 10, 16)
+			if err != nil {
+				ErrorMessage(errors.New("Invalid timeout value"))
+				return
+			}
+
+			options.Timeout = uint16(value)
+		}
+
+		modal.Exit(false)
+
+		go startAdvertising(adapter, options)
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit(false)
+	})
+
+	modal.Show()
+}
+
+// startAdvertising starts advertising from the adapter.
+func startAdvertising(adapter bluez.Adapter, options bluez.AdvertisementOptions) {
+	if err := UI.Bluez.StartAdvertising(adapter.Path, options); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot start advertising"))
+		return
+	}
+
+	setMenuItemToggle("adapter", cmd.KeyAdapterToggleAdvertise, true)
+	InfoMessage("Advertising from "+bluez.GetAdapterID(adapter.Path), false)
+}
+
+// advertisingReleased updates the advertising state of the adapter,
+// when its advertisement is removed by BlueZ.
+func advertisingReleased(adapterPath string) {
+	if UI.Bluez.GetCurrentAdapter().Path == adapterPath {
+		setMenuItemToggle("adapter", cmd.KeyAdapterToggleAdvertise, false)
+	}
+
+	InfoMessage("Stopped advertising from "+bluez.GetAdapterID(adapterPath), false)
+}
+
+// parseManufacturerData parses a comma-separated list of "id:hex" pairs.
+func parseManufacturerData(text string) (map[uint16][]byte, error) {
+	data, err := parseAdvertisingData(text)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	manufacturerData := make(map[uint16][]byte, len(data))
+	for key, value := range data {
+		id, err := strconv.ParseUint(key, 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid manufacturer ID '%s'", key)
+		}
+
+		manufacturerData[uint16(id)] = value
+	}
+
+	return manufacturerData, nil
+}
+
+// parseAdvertisingData parses a comma-separated list of "key:hex" pairs.
+func parseAdvertisingData(text string) (map[string][]byte, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	data := make(map[string][]byte)
+
+	for _, pair := range strings.Split(text, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("Invalid advertising data '%s'", pair)
+		}
+
+		parsedValue, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid advertising data value '%s'", value)
+		}
+
+		data[key] = parsedValue
+	}
+
+	return data, nil
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/notify"
+	"github.com/godbus/dbus/v5"
+)
+
+// AgentHandler responds to the pairing and OBEX agent requests via the UI.
+type AgentHandler struct{}
+
+// Bluez returns the bluez DBus connection of the UI.
+func (AgentHandler) Bluez() *bluez.Bluez {
+	return UI.Bluez
+}
+
+// Obex returns the bluez OBEX DBus connection of the UI.
+func (AgentHandler) Obex() *bluez.Obex {
+	return UI.Obex
+}
+
+// GetDevice returns the device from the UI's device store.
+func (AgentHandler) GetDevice(devicePath string) (bluez.Device, error) {
+	return GetDeviceFromPath(devicePath)
+}
+
+// DisplayPinCode shows a modal and a notification with the pincode.
+func (AgentHandler) DisplayPinCode(device bluez.Device, pincode string) {
+	msg := fmt.Sprintf(
+		"The pincode for [::bu]%s[-:-:-] is:\n\n[::b]%s[-:-:-]",
+		device.Name, pincode,
+	)
+
+	NewDisplayModal("pincode", "Pin Code", msg)
+	SendNotification(NotifyPairing, "Pin Code", fmt.Sprintf("The pincode for %s is %s", device.Name, pincode))
+}
+
+// DisplayPasskey shows a modal and a notification with the passkey.
+func (AgentHandler) DisplayPasskey(device bluez.Device, passkey uint32, entered uint16) {
+	msg := fmt.Sprintf(
+		"The passkey for [::bu]%s[-:-:-] is:\n\n[::b]%d[-:-:-]",
+		device.Name, passkey,
+	)
+	if entered > 0 {
+		msg += fmt.Sprintf("\n\nYou have entered %d", entered)
+	}
+
+	NewDisplayModal("passkey-display", "Passkey Display", msg)
+	SendNotification(NotifyPairing, "Passkey Display", fmt.Sprintf("The passkey for %s is %d", device.Name, passkey))
+}
+
+// RequestPinCode always provides the pincode, since pairing
+// is initiated from the UI.
+func (AgentHandler) RequestPinCode(device bluez.Device, pincode string) bool {
+	return true
+}
+
+// RequestPasskey always provides the passkey, since pairing
+// is initiated from the UI.
+func (AgentHandler) RequestPasskey(device bluez.Device, passkey uint32) bool {
+	return true
+}
+
+// ConfirmPasskey asks for confirmation of the passkey.
+func (AgentHandler) ConfirmPasskey(device bluez.Device, passkey uint32) bool {
+	msg := fmt.Sprintf(
+		"Confirm passkey for [::bu]%s[-:-:-] is \n\n[::b]%d[-:-:-]",
+		device.Name, passkey,
+	)
+
+	return ConfirmWithNotification(
+		NotifyPairing, "passkey-confirm", "Passkey Confirmation", msg,
+		fmt.Sprintf("Confirm passkey for %s is %d", device.Name, passkey),
+	) == "y"
+}
+
+// ConfirmPairing asks for confirmation before pairing.
+func (AgentHandler) ConfirmPairing(device bluez.Device) bool {
+	msg := fmt.Sprintf("Confirm pairing with [::bu]%s[-:-:-]", device.Name)
+
+	return ConfirmWithNotification(
+		NotifyPairing, "pairing-confirm", "Pairing Confirmation", msg,
+		fmt.Sprintf("Confirm pairing with %s", device.Name),
+	) == "y"
+}
+
+// TrustDevice always marks the device as trusted, since
+// pairing was confirmed by the user.
+func (AgentHandler) TrustDevice(device bluez.Device) bool {
+	return true
+}
+
+// AuthorizeService asks for confirmation before authorizing a service UUID.
+func (AgentHandler) AuthorizeService(devicePath dbus.ObjectPath, uuid string) string {
+	return SetInput(fmt.Sprintf("Authorize service %s (y/n/a)", uuid))
+}
+
+// AuthorizePush asks for confirmation before receiving a file.
+func (AgentHandler) AuthorizePush(address, name string) string {
+	return InputWithNotification(
+		NotifyPush, "Accept file "+filepath.Base(name)+" (y/n/a)?", "File Transfer",
+		"Accept file "+filepath.Base(name)+" from "+address+"?",
+		notify.Action{Key: "y", Label: "Accept"},
+		notify.Action{Key: "n", Label: "Reject"},
+		notify.Action{Key: "a", Label: "Accept all"},
+	)
+}
+
+// ReceiveFile displays the progress of the transfer.
+func (AgentHandler) ReceiveFile(transferPath dbus.ObjectPath, props bluez.ObexTransferProperties, path string) {
+	StartProgress(transferPath, props, path)
+}
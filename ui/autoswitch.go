@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/pkg/errors"
+)
+
+// ProfileSwitcher stores the devices whose audio profiles were
+// automatically switched to a headset profile.
+type ProfileSwitcher struct {
+	switched map[string]string
+	pending  map[string]*time.Timer
+	timers   map[string]*time.Timer
+
+	lock sync.Mutex
+}
+
+const (
+	// profileSwitchDelay is the time to wait after the last capture stream
+	// of a device has closed, before switching back to its previous profile.
+	profileSwitchDelay = 3 * time.Second
+
+	// profileSwitchDebounce is the time to wait after a capture stream
+	// has opened, before switching to the headset profile.
+	profileSwitchDebounce = 500 * time.Millisecond
+)
+
+var switcher ProfileSwitcher
+
+// profileSwitchEvent handles capture stream events, and switches
+// the audio profiles of devices for which the "auto-switch-profile"
+// setting is enabled.
+func profileSwitchEvent(event bluez.AudioEvent) {
+	if UI.Bluez == nil || (!event.Added && !event.Removed) {
+		return
+	}
+
+	switcher.lock.Lock()
+	if switcher.switched == nil {
+		switcher.switched = make(map[string]string)
+		switcher.pending = make(map[string]*time.Timer)
+		switcher.timers = make(map[string]*time.Timer)
+	}
+	switcher.lock.Unlock()
+
+	for _, device := range UI.Bluez.GetDevices() {
+		if !device.Connected || !cmd.IsDevicePropertyEnabled(device.Address, "auto-switch-profile") {
+			continue
+		}
+
+		if event.Removed {
+			scheduleProfileRestore(device)
+			continue
+		}
+
+		scheduleHeadsetSwitch(device, event.Index)
+	}
+}
+
+// scheduleHeadsetSwitch switches the device to its headset profile, if the
+// capture stream is still open after the debounce interval.
+func scheduleHeadsetSwitch(device bluez.Device, streamIndex uint32) {
+	switcher.lock.Lock()
+	defer switcher.lock.Unlock()
+
+	if timer, ok := switcher.timers[device.Address]; ok {
+		timer.Stop()
+		delete(switcher.timers, device.Address)
+	}
+
+	if timer, ok := switcher.pending[device.Address]; ok {
+		timer.Stop()
+	}
+
+	switcher.pending[device.Address] = time.AfterFunc(profileSwitchDebounce, func() {
+		switcher.lock.Lock()
+		delete(switcher.pending, device.Address)
+		switcher.lock.Unlock()
+
+		switchToHeadset(device, streamIndex)
+	})
+}
+
+// switchToHeadset switches the device to its best headset profile if the capture
+// stream records from the default source or the device while the device is the
+// default output, and moves the capture stream to the device.
+func switchToHeadset(device bluez.Device, streamIndex uint32) {
+	switcher.lock.Lock()
+	_, switched := switcher.switched[device.Address]
+	switcher.lock.Unlock()
+
+	if switched {
+		return
+	}
+
+	stream, err := bluez.GetCaptureStream(streamIndex)
+	if err != nil || !bluez.IsCaptureSource(device.Address, stream.SourceIndex) {
+		return
+	}
+
+	profiles, err := bluez.ListAudioProfiles(device.Address)
+	if err != nil {
+		return
+	}
+
+	var active, headset bluez.AudioProfile
+	for _, profile := range profiles {
+		if profile.Active {
+			active = profile
+		}
+
+		if profile.Sinks > 0 && profile.Sources > 0 && profile.Priority >= headset.Priority {
+			headset = profile
+		}
+	}
+
+	if active.Sources > 0 || headset.Name == "" || !bluez.IsDefaultSink(device.Address) {
+		return
+	}
+
+	switcher.lock.Lock()
+	if _, ok := switcher.switched[device.Address]; ok {
+		switcher.lock.Unlock()
+		return
+	}
+	switcher.switched[device.Address] = active.Name
+	switcher.lock.Unlock()
+
+	if err := bluez.SetDeviceAudioProfile(device.Address, headset.Name); err != nil {
+		switcher.lock.Lock()
+		delete(switcher.switched, device.Address)
+		switcher.lock.Unlock()
+
+		ErrorMessage(errors.Wrap(err, "Cannot switch to headset profile for "+device.Name))
+		return
+	}
+
+	if err := bluez.WaitForAudioSource(device.Address, audioCardTimeout); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if err := bluez.MoveCaptureStream(device.Address, streamIndex); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Switched "+device.Name+" to "+headset.Description, false)
+}
+
+// scheduleProfileRestore switches the device back to its previous profile,
+// once no capture streams are recording from the device.
+func scheduleProfileRestore(device bluez.Device) {
+	switcher.lock.Lock()
+	_, switched := switcher.switched[device.Address]
+	switcher.lock.Unlock()
+
+	if !switched || hasCaptureStreams(device) {
+		return
+	}
+
+	switcher.lock.Lock()
+	defer switcher.lock.Unlock()
+
+	if timer, ok := switcher.timers[device.Address]; ok {
+		timer.Reset(profileSwitchDelay)
+		return
+	}
+
+	switcher.timers[device.Address] = time.AfterFunc(profileSwitchDelay, func() {
+		restoreProfile(device)
+	})
+}
+
+// restoreProfile switches the device back to its previous profile,
+// if no capture streams are recording from the device.
+func restoreProfile(device bluez.Device) {
+	switcher.lock.Lock()
+	delete(switcher.timers, device.Address)
+	profileName, ok := switcher.switched[device.Address]
+	switcher.lock.Unlock()
+
+	if !ok || hasCaptureStreams(device) {
+		return
+	}
+
+	switcher.lock.Lock()
+	delete(switcher.switched, device.Address)
+	switcher.lock.Unlock()
+
+	if err := bluez.SetDeviceAudioProfile(device.Address, profileName); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot restore audio profile for "+device.Name))
+		return
+	}
+
+	InfoMessage("Switched "+device.Name+" back to "+profileName, false)
+
+	autoRouteAudio(device)
+}
+
+// hasCaptureStreams returns whether any capture streams are
+// recording from the device.
+func hasCaptureStreams(device bluez.Device) bool {
+	streams, err := bluez.ListCaptureStreams()
+	if err != nil {
+		return false
+	}
+
+	sources, err := bluez.ListAudioSources(device.Address)
+	if err != nil {
+		return false
+	}
+
+	for _, stream := range streams {
+		for _, source := range sources {
+			if stream.SourceIndex == source.Index {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/notify"
+)
+
+// BatteryRecord stores the battery percentage of a device at a point in time.
+type BatteryRecord struct {
+	Time       int64 `json:"time"`
+	Percentage int   `json:"percentage"`
+}
+
+// BatteryHistory stores the battery records of each device,
+// mapped to the device's address.
+type BatteryHistory struct {
+	records map[string][]BatteryRecord
+	warned  map[string]bool
+
+	lock sync.Mutex
+}
+
+const (
+	// batteryHistorySize is the maximum number of records stored per device.
+	batteryHistorySize = 64
+
+	// batterySparkWidth is the number of records shown in the sparkline.
+	batterySparkWidth = 32
+)
+
+var (
+	batteryHistory BatteryHistory
+	sparkBlocks    = []rune("▁▂▃▄▅▆▇█")
+)
+
+// batteryEvent records the battery percentage of the device,
+// and warns if it falls below the device's configured threshold.
+func batteryEvent(device bluez.Device) {
+	if !device.Connected || device.Percentage <= 0 {
+		return
+	}
+
+	if !recordBattery(device) {
+		return
+	}
+
+	checkBatteryThreshold(device)
+}
+
+// recordBattery adds the battery percentage of the device to the history,
+// and saves the history. It returns false if the percentage is unchanged.
+func recordBattery(device bluez.Device) bool {
+	batteryHistory.lock.Lock()
+	defer batteryHistory.lock.Unlock()
+
+	loadBatteryHistory()
+
+	records := batteryHistory.records[device.Address]
+	if len(records) > 0 && records[len(records)-1].Percentage == device.Percentage {
+		return false
+	}
+
+	records = append(records, BatteryRecord{
+		Time:       time.Now().Unix(),
+		Percentage: device.Percentage,
+	})
+	if len(records) > batteryHistorySize {
+		records = records[len(records)-batteryHistorySize:]
+	}
+
+	batteryHistory.records[device.Address] = records
+
+	if err := saveBatteryHistory(); err != nil {
+		ErrorMessage(err)
+	}
+
+	return true
+}
+
+// checkBatteryThreshold shows a warning and sends a desktop notification
+// once the battery percentage of the device falls to or below its threshold.
+func checkBatteryThreshold(device bluez.Device) {
+	threshold := batteryThreshold(device.Address)
+	if threshold <= 0 {
+		return
+	}
+
+	batteryHistory.lock.Lock()
+	if device.Percentage > threshold {
+		delete(batteryHistory.warned, device.Address)
+		batteryHistory.lock.Unlock()
+
+		return
+	}
+
+	warned := batteryHistory.warned[device.Address]
+	batteryHistory.warned[device.Address] = true
+	batteryHistory.lock.Unlock()
+
+	if warned {
+		return
+	}
+
+	text := "Battery low on " + device.Name + " (" + strconv.Itoa(device.Percentage) + "%)"
+
+	WarningMessage(text)
+	SendNotification(NotifyBattery, "Battery low", text, notify.UrgencyCritical)
+}
+
+// batteryThreshold returns the low battery threshold of the device.
+// If no threshold is set for the device, the global threshold is used.
+func batteryThreshold(address string) int {
+	value := cmd.GetDeviceProperty(address, "battery-threshold")
+	if value == "" {
+		value = cmd.GetProperty("battery-threshold")
+	}
+
+	threshold, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+
+	return threshold
+}
+
+// batteryInfo returns the battery percentage, the battery history as a sparkline
+// and the estimated remaining time of the device.
+func batteryInfo(device bluez.Device) (string, string) {
+	batteryHistory.lock.Lock()
+	defer batteryHistory.lock.Unlock()
+
+	loadBatteryHistory()
+
+	records := batteryHistory.records[device.Address]
+
+	percentage := strconv.Itoa(device.Percentage) + "%"
+	if remaining, ok := batteryEstimate(records); ok {
+		percentage += " (about " + remaining.String() + " remaining)"
+	}
+
+	return percentage, batterySparkline(records)
+}
+
+// batterySparkline returns the battery records as a sparkline.
+func batterySparkline(records []BatteryRecord) string {
+	var spark strings.Builder
+
+	if len(records) > batterySparkWidth {
+		records = records[len(records)-batterySparkWidth:]
+	}
+
+	for _, record := range records {
+		index := record.Percentage * (len(sparkBlocks) - 1) / 100
+		if index < 0 {
+			index = 0
+		}
+
+		spark.WriteRune(sparkBlocks[index])
+	}
+
+	return spark.String()
+}
+
+// batteryEstimate estimates the remaining battery time from the records
+// since the device was last charged.
+func batteryEstimate(records []BatteryRecord) (time.Duration, bool) {
+	start := len(records) - 1
+	for start > 0 && records[start-1].Percentage > records[start].Percentage {
+		start--
+	}
+
+	if start < 0 || start == len(records)-1 {
+		return 0, false
+	}
+
+	first, last := records[start], records[len(records)-1]
+
+	elapsed := last.Time - first.Time
+	drained := first.Percentage - last.Percentage
+	if elapsed <= 0 || drained <= 0 {
+		return 0, false
+	}
+
+	remaining := time.Duration(int64(last.Percentage)*elapsed/int64(drained)) * time.Second
+
+	return remaining.Round(time.Minute), true
+}
+
+// loadBatteryHistory loads the battery history from the history file.
+func loadBatteryHistory() {
+	if batteryHistory.records != nil {
+		return
+	}
+
+	batteryHistory.records = make(map[string][]BatteryRecord)
+	batteryHistory.warned = make(map[string]bool)
+
+	historyFile, err := cmd.ConfigPath("battery-history")
+	if err != nil {
+		return
+	}
+
+	data, err := os.ReadFile(historyFile)
+	if err != nil || len(data) == 0 {
+		return
+	}
+
+	json.Unmarshal(data, &batteryHistory.records)
+}
+
+// saveBatteryHistory saves the battery history to the history file.
+func saveBatteryHistory() error {
+	historyFile, err := cmd.ConfigPath("battery-history")
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(batteryHistory.records)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(historyFile, data, 0600)
+}
@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// DeviceColumn describes a column of the device table.
+type DeviceColumn struct {
+	// Width is the minimum width of the column. If the column
+	// does not fit within the device table, it is hidden.
+	Width int
+
+	Align     int
+	Expansion int
+
+	text func(device bluez.Device, columns []string) string
+}
+
+// deviceColumnWidth stores the width of the device table,
+// which was used to determine the visible columns.
+var deviceColumnWidth int
+
+var (
+	defaultColumns = []string{"name", "properties", "volume"}
+
+	deviceColumns = map[string]DeviceColumn{
+		"name": {
+			Width: 20, Align: tview.AlignLeft, Expansion: 1,
+			text: deviceNameText,
+		},
+		"properties": {
+			Width: 20, Align: tview.AlignRight, Expansion: 1,
+			text: devicePropertiesText,
+		},
+		"address": {
+			Width: 17, Align: tview.AlignLeft,
+			text: func(device bluez.Device, _ []string) string {
+				return device.Address
+			},
+		},
+		"address-type": {
+			Width: 6, Align: tview.AlignLeft,
+			text: func(device bluez.Device, _ []string) string {
+				return device.AddressType
+			},
+		},
+		"rssi": {
+			Width: 8, Align: tview.AlignRight,
+			text: func(device bluez.Device, _ []string) string {
+				if device.RSSI >= 0 {
+					return ""
+				}
+
+				return strconv.FormatInt(int64(device.RSSI), 10) + " dBm"
+			},
+		},
+		"battery": {
+			Width: 4, Align: tview.AlignRight,
+			text: func(device bluez.Device, _ []string) string {
+				if device.Percentage <= 0 {
+					return ""
+				}
+
+				return strconv.Itoa(device.Percentage) + "%"
+			},
+		},
+		"type": {
+			Width: 10, Align: tview.AlignLeft,
+			text: func(device bluez.Device, _ []string) string {
+				return device.Type
+			},
+		},
+		"profile": {
+			Width: 20, Align: tview.AlignLeft,
+			text: func(device bluez.Device, _ []string) string {
+				if !device.Connected {
+					return ""
+				}
+
+				return getConnectedProfiles(device.Path)
+			},
+		},
+		"flags": {
+			Width: 16, Align: tview.AlignLeft,
+			text: func(device bluez.Device, _ []string) string {
+				var flags []string
+
+				if device.Trusted {
+					flags = append(flags, "Trusted")
+				}
+				if device.Blocked {
+					flags = append(flags, "Blocked")
+				}
+
+				return strings.Join(flags, ", ")
+			},
+		},
+		"last-seen": {
+			Width: 8, Align: tview.AlignRight,
+			text: func(device bluez.Device, _ []string) string {
+				deviceView.lock.Lock()
+				defer deviceView.lock.Unlock()
+
+				lastSeen, ok := deviceView.lastSeen[device.Path]
+				if !ok {
+					return ""
+				}
+
+				return lastSeen.Format("15:04:05")
+			},
+		},
+		"vendor": {
+			Width: 12, Align: tview.AlignLeft,
+			text: func(device bluez.Device, _ []string) string {
+				return bluez.DeviceVendor(device)
+			},
+		},
+		"volume": {
+			Width: 10, Align: tview.AlignRight,
+			text: func(device bluez.Device, _ []string) string {
+				return deviceVolumeText(device)
+			},
+		},
+	}
+)
+
+// setDeviceColumns writes the device information into the
+// visible columns of the specified row of the DeviceTable.
+func setDeviceColumns(row int, device bluez.Device, nameColor, propColor theme.ThemeContext) {
+	columns := visibleColumns()
+
+	for col, name := range columns {
+		column := deviceColumns[name]
+
+		cell := tview.NewTableCell(column.text(device, columns)).
+			SetExpansion(column.Expansion).
+			SetAlign(column.Align).
+			SetTextColor(theme.GetColor(propColor)).
+			SetSelectedStyle(tcell.Style{}.
+				Bold(true),
+			)
+
+		if name == "name" {
+			cell.SetAttributes(tcell.AttrBold).
+				SetTextColor(theme.GetColor(nameColor)).
+				SetSelectedStyle(tcell.Style{}.
+					Foreground(theme.GetColor(nameColor)).
+					Background(theme.BackgroundColor(nameColor)),
+				)
+		}
+
+		if col == 0 {
+			cell.SetReference(device)
+		}
+
+		DeviceTable.SetCell(row, col, cell)
+	}
+}
+
+// visibleColumns returns the configured columns which fit
+// within the device table.
+func visibleColumns() []string {
+	_, _, tableWidth, _ := DeviceTable.GetInnerRect()
+
+	return visibleColumnsAt(tableWidth)
+}
+
+// ResizeDeviceTable redraws the device table if the visible
+// columns have changed after the table was resized.
+func ResizeDeviceTable() {
+	_, _, tableWidth, _ := DeviceTable.GetInnerRect()
+	if tableWidth == deviceColumnWidth {
+		return
+	}
+
+	previous := deviceColumnWidth
+	deviceColumnWidth = tableWidth
+
+	if previous != 0 && len(visibleColumnsAt(previous)) == len(visibleColumns()) {
+		return
+	}
+
+	refreshDeviceTable()
+}
+
+// visibleColumnsAt returns the configured columns which fit within
+// the provided table width. The first column is always visible.
+func visibleColumnsAt(tableWidth int) []string {
+	var visible []string
+	var width int
+
+	columns := defaultColumns
+	if option := cmd.GetProperty("columns"); option != "" {
+		columns = strings.Split(option, ",")
+	}
+
+	for i, name := range columns {
+		column, ok := deviceColumns[name]
+		if !ok {
+			continue
+		}
+
+		width += column.Width + 1
+		if i > 0 && tableWidth > 0 && width > tableWidth {
+			break
+		}
+
+		visible = append(visible, name)
+	}
+
+	return visible
+}
+
+// isColumnEnabled returns whether the column is configured to be shown.
+func isColumnEnabled(name string) bool {
+	return containsString(visibleColumnsAt(0), name)
+}
+
+// deviceNameText returns the name of the device, along with its alias
+// and type. The type is omitted if it is displayed in its own column.
+func deviceNameText(device bluez.Device, columns []string) string {
+	var data []string
+
+	if !containsString(columns, "type") {
+		data = append(data, theme.ColorWrap(theme.ThemeDeviceType, device.Type))
+	}
+
+	name := device.Name
+	if name == "" {
+		name = device.Address
+	}
+	if device.Alias != device.Name {
+		data = append(
+			[]string{theme.ColorWrap(theme.ThemeDeviceAlias, device.Alias)},
+			data...,
+		)
+	}
+	if data != nil {
+		name += " (" + strings.Join(data, ", ") + ")"
+	}
+
+	return name
+}
+
+// devicePropertiesText returns the connection, battery, trust,
+// block and pairing states of the device.
+func devicePropertiesText(device bluez.Device, _ []string) string {
+	var props string
+
+	if device.Connected {
+		props += "Connected"
+
+		if device.RSSI < 0 {
+			rssi := strconv.FormatInt(int64(device.RSSI), 10)
+			props += "[" + rssi + "[]"
+		}
+
+		if device.Percentage > 0 {
+			props += ", Battery " + strconv.Itoa(device.Percentage) + "%"
+		}
+
+		props += ", "
+	}
+
+	if status := reconnectStatus(device); status != "" {
+		props += status + ", "
+	}
+
+	if device.Trusted {
+		props += "Trusted, "
+	}
+	if device.Blocked {
+		props += "Blocked, "
+	}
+	if device.Bonded && device.Paired {
+		props += "Bonded, "
+	} else if !device.Bonded && device.Paired {
+		props += "Paired, "
+	}
+
+	if props == "" {
+		return "[New Device[]"
+	}
+
+	return "(" + strings.TrimRight(props, ", ") + ")"
+}
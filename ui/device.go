@@ -1,10 +1,8 @@
 package ui
 
 import (
-	"fmt"
 	"path/filepath"
 	"strconv"
-	"strings"
 
 	"github.com/darkhz/bluetuith/bluez"
 	"github.com/darkhz/bluetuith/cmd"
@@ -60,6 +58,8 @@ func deviceTable() *tview.Table {
 func setupDevices() {
 	listDevices()
 	go watchEvent()
+	go watchAudioEvent()
+	go watchSleepEvent()
 }
 
 // listDevices lists the devices belonging to the selected adapter.
@@ -68,17 +68,16 @@ func listDevices() {
 		return
 	}
 
-	headerText := fmt.Sprintf("[\"adapterchange\"]%s (%s)[\"\"]",
-		UI.Bluez.GetCurrentAdapter().Name,
-		UI.Bluez.GetCurrentAdapterID(),
-	)
-	setMenuBarHeader(theme.ColorWrap(theme.ThemeAdapter, headerText, "::bu"))
+	setAdapterHeader(UI.Bluez.GetCurrentAdapter())
 
 	DeviceTable.Clear()
-	for i, device := range UI.Bluez.GetDevices() {
-		setDeviceTableInfo(i, device)
+	refreshDeviceTable()
+
+	for _, device := range UI.Bluez.GetDevices() {
+		if device.Connected {
+			go refreshConnectedProfiles(device)
+		}
 	}
-	DeviceTable.Select(0, 0)
 }
 
 // connectDeviceByAddress connects to a device based on the provided address
@@ -145,6 +144,14 @@ func getDeviceInfo() {
 	if device.Modalias != "" {
 		props = append(props, []string{"Modalias", device.Modalias})
 	}
+	if device.Percentage > 0 {
+		percentage, history := batteryInfo(device)
+
+		props = append(props, []string{"Battery", percentage})
+		if history != "" {
+			props = append(props, []string{"Battery History", history})
+		}
+	}
 	props = append(props, []string{"UUIDs", ""})
 
 	infoModal := NewModal("info", "Device Information", nil, 40, 100)
@@ -244,85 +251,20 @@ func getDeviceFromSelection(lock bool) bluez.Device {
 // setDeviceTableInfo writes device information into the
 // specified row of the DeviceTable.
 func setDeviceTableInfo(row int, device bluez.Device) {
-	var props string
-
-	data := []string{
-		theme.ColorWrap(theme.ThemeDeviceType, device.Type),
-	}
-	name := device.Name
-	if name == "" {
-		name = device.Address
-	}
-	if device.Alias != device.Name {
-		data = append(
-			[]string{theme.ColorWrap(theme.ThemeDeviceAlias, device.Alias)},
-			data...,
-		)
-	}
-	name += " (" + strings.Join(data, ", ") + ")"
-
 	nameColor := theme.ThemeDevice
 	propColor := theme.ThemeDeviceProperty
 
-	if device.Connected {
-		props += "Connected"
-
+	switch {
+	case device.Connected:
 		nameColor = theme.ThemeDeviceConnected
 		propColor = theme.ThemeDevicePropertyConnected
 
-		if device.RSSI < 0 {
-			rssi := strconv.FormatInt(int64(device.RSSI), 10)
-			props += "[" + rssi + "[]"
-		}
-
-		if device.Percentage > 0 {
-			props += ", Battery " + strconv.Itoa(device.Percentage) + "%"
-		}
-
-		props += ", "
-	}
-
-	if device.Trusted {
-		props += "Trusted, "
-	}
-	if device.Blocked {
-		props += "Blocked, "
-	}
-	if device.Bonded && device.Paired {
-		props += "Bonded, "
-	} else if !device.Bonded && device.Paired {
-		props += "Paired, "
-	}
-
-	if props != "" {
-		props = "(" + strings.TrimRight(props, ", ") + ")"
-	} else {
-		props = "[New Device[]"
+	case !device.Trusted && !device.Blocked && !device.Paired:
 		nameColor = theme.ThemeDeviceDiscovered
 		propColor = theme.ThemeDevicePropertyDiscovered
 	}
 
-	DeviceTable.SetCell(
-		row, 0, tview.NewTableCell(name).
-			SetExpansion(1).
-			SetReference(device).
-			SetAlign(tview.AlignLeft).
-			SetAttributes(tcell.AttrBold).
-			SetTextColor(theme.GetColor(nameColor)).
-			SetSelectedStyle(tcell.Style{}.
-				Foreground(theme.GetColor(nameColor)).
-				Background(theme.BackgroundColor(nameColor)),
-			),
-	)
-	DeviceTable.SetCell(
-		row, 1, tview.NewTableCell(props).
-			SetExpansion(1).
-			SetAlign(tview.AlignRight).
-			SetTextColor(theme.GetColor(propColor)).
-			SetSelectedStyle(tcell.Style{}.
-				Bold(true),
-			),
-	)
+	setDeviceColumns(row, device, nameColor, propColor)
 }
 
 // deviceEvent handles device-specific events.
@@ -334,35 +276,53 @@ func deviceEvent(signal *dbus.Signal, signalData interface{}) {
 			return
 		}
 
+		markDeviceSeen(device)
+
 		UI.QueueUpdateDraw(func() {
-			row, ok := checkDeviceTable(device.Path)
-			if ok {
-				setDeviceTableInfo(row, device)
-			}
+			updateDeviceTable(device)
 		})
 
+		connected, changed := deviceConnectionChanged(signal)
+		if _, uuidsChanged := signalPropertyChanged(signal, "org.bluez.Device1", "UUIDs"); changed || uuidsChanged {
+			go refreshConnectedProfiles(device)
+		}
+		if changed {
+			if connected {
+				go setupDeviceAudio(device)
+			}
+
+			go notifyConnection(device, connected)
+		}
+
+		_, discovered := signalPropertyChanged(signal, "org.bluez.Device1", "RSSI")
+		go reconnectDeviceEvent(device, connected, changed, discovered)
+
+		go batteryEvent(device)
+
 	case "org.freedesktop.DBus.ObjectManager.InterfacesAdded":
 		deviceMap, ok := signalData.(map[string][]bluez.Device)
 		if !ok {
 			return
 		}
 
-		for devicePath, devices := range deviceMap {
+		for _, devices := range deviceMap {
 			for _, device := range devices {
-				if device.Adapter != UI.Bluez.GetCurrentAdapter().Path {
+				device := device
+
+				if !isAdapterShown(device.Adapter) {
 					continue
 				}
 
-				UI.QueueUpdateDraw(func() {
-					deviceRow := DeviceTable.GetRowCount()
+				markDeviceSeen(device)
 
-					row, ok := checkDeviceTable(devicePath)
-					if ok {
-						deviceRow = row
-					}
-
-					setDeviceTableInfo(deviceRow, device)
+				UI.QueueUpdateDraw(func() {
+					updateDeviceTable(device)
 				})
+
+				go func() {
+					resetReconnect(device)
+					reconnectDeviceEvent(device, false, false, true)
+				}()
 			}
 		}
 
@@ -380,3 +340,38 @@ func deviceEvent(signal *dbus.Signal, signalData interface{}) {
 		})
 	}
 }
+
+// deviceConnectionChanged returns the connection state of the device,
+// and whether the signal indicates that the connection state has changed.
+func deviceConnectionChanged(signal *dbus.Signal) (bool, bool) {
+	value, ok := signalPropertyChanged(signal, "org.bluez.Device1", "Connected")
+	if !ok {
+		return false, false
+	}
+
+	connected, ok := value.Value().(bool)
+
+	return connected, ok
+}
+
+// signalPropertyChanged returns the new value of the property,
+// and whether the signal indicates that the property of the
+// provided interface has changed.
+func signalPropertyChanged(signal *dbus.Signal, iface, property string) (dbus.Variant, bool) {
+	if signal.Name != "org.freedesktop.DBus.Properties.PropertiesChanged" || len(signal.Body) < 2 {
+		return dbus.Variant{}, false
+	}
+
+	if name, ok := signal.Body[0].(string); !ok || name != iface {
+		return dbus.Variant{}, false
+	}
+
+	changed, ok := signal.Body[1].(map[string]dbus.Variant)
+	if !ok {
+		return dbus.Variant{}, false
+	}
+
+	value, ok := changed[property]
+
+	return value, ok
+}
@@ -0,0 +1,575 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+)
+
+// DeviceView stores the search text, filters and sort key
+// which are applied to the device table.
+type DeviceView struct {
+	search   string
+	lastSeen map[string]time.Time
+
+	lock sync.Mutex
+}
+
+// DeviceFilter describes a filter that can be toggled
+// from the filter menu.
+type DeviceFilter struct {
+	Name, Title string
+}
+
+// DeviceSort describes a sort key that can be selected
+// from the sort menu.
+type DeviceSort struct {
+	Name, Title string
+}
+
+var (
+	deviceView DeviceView
+
+	deviceFilters = []DeviceFilter{
+		{"paired", "Paired only"},
+		{"connected", "Connected only"},
+		{"hide-unnamed", "Hide unnamed"},
+	}
+
+	deviceSortKeys = []DeviceSort{
+		{"", "Default"},
+		{"name", "Name"},
+		{"rssi", "RSSI"},
+		{"last-seen", "Last Seen"},
+		{"battery", "Battery"},
+	}
+)
+
+// searchDevices shows an input field to incrementally
+// search for devices in the device table.
+func searchDevices() {
+	exit := func() {
+		UI.Status.InputField.SetChangedFunc(nil)
+		UI.Status.SwitchToPage("messages")
+
+		_, item := UI.Pages.GetFrontPage()
+		UI.SetFocus(item)
+	}
+
+	UI.Status.InputField.SetText(getDeviceSearch())
+	UI.Status.InputField.SetLabel("[::b]Search: ")
+	UI.Status.InputField.SetAcceptanceFunc(nil)
+	UI.Status.InputField.SetChangedFunc(func(text string) {
+		setDeviceSearch(text)
+		refreshDeviceTable()
+	})
+	UI.Status.InputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch cmd.KeyOperation(event) {
+		case cmd.KeySelect:
+			exit()
+
+		case cmd.KeyClose:
+			exit()
+
+			setDeviceSearch("")
+			refreshDeviceTable()
+		}
+
+		return event
+	})
+
+	UI.Status.SwitchToPage("input")
+	UI.SetFocus(UI.Status.InputField)
+}
+
+// filterMenu shows a popup to toggle the device filters.
+func filterMenu() {
+	types := []string{""}
+	for _, device := range UI.Bluez.GetDevices() {
+		if device.Type == "" || containsString(types, device.Type) {
+			continue
+		}
+
+		types = append(types, device.Type)
+	}
+	sort.Strings(types)
+
+	setContextMenu(
+		"filter",
+		func(filterMenu *tview.Table) {
+			row, _ := filterMenu.GetSelection()
+
+			setFilter(filterMenu, row, 0)
+		}, nil,
+		func(filterMenu *tview.Table) (int, int) {
+			var width int
+
+			filterMenu.SetSelectorWrap(true)
+
+			addRow := func(row int, title string, reference interface{}) {
+				if len(title) > width {
+					width = len(title)
+				}
+
+				filterMenu.SetCell(row, 1, tview.NewTableCell(title).
+					SetExpansion(1).
+					SetReference(reference).
+					SetAlign(tview.AlignLeft).
+					SetOnClickedFunc(setFilter).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(tcell.Style{}.
+						Foreground(theme.GetColor(theme.ThemeText)).
+						Background(theme.BackgroundColor(theme.ThemeText)),
+					),
+				)
+			}
+
+			for row, filter := range deviceFilters {
+				addRow(row, filter.Title, filter)
+			}
+
+			for i, deviceType := range types {
+				title := "Type: " + deviceType
+				if deviceType == "" {
+					title = "Type: All"
+				}
+
+				addRow(len(deviceFilters)+i, title, deviceType)
+			}
+
+			markFilters(filterMenu)
+
+			return width - 16, 0
+		},
+	)
+}
+
+// setFilter toggles the selected filter.
+func setFilter(filterMenu *tview.Table, row, column int) {
+	cell := filterMenu.GetCell(row, 1)
+	if cell == nil {
+		return
+	}
+
+	var err error
+
+	switch ref := cell.GetReference().(type) {
+	case DeviceFilter:
+		err = cmd.SaveProperty("view.filter."+ref.Name, !isFilterEnabled(ref.Name))
+
+	case string:
+		err = cmd.SaveProperty("view.filter.type", ref)
+	}
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot save device filter"))
+	}
+
+	markFilters(filterMenu)
+	refreshDeviceTable()
+}
+
+// markFilters marks the enabled filters in the filter menu.
+func markFilters(filterMenu *tview.Table) {
+	for row := 0; row < filterMenu.GetRowCount(); row++ {
+		var enabled bool
+
+		cell := filterMenu.GetCell(row, 1)
+		if cell == nil {
+			continue
+		}
+
+		switch ref := cell.GetReference().(type) {
+		case DeviceFilter:
+			enabled = isFilterEnabled(ref.Name)
+
+		case string:
+			enabled = ref == cmd.GetProperty("view.filter.type")
+		}
+
+		setMenuMarker(filterMenu, row, enabled)
+	}
+}
+
+// sortMenu shows a popup to select the sort key of the device table.
+func sortMenu() {
+	setContextMenu(
+		"sort",
+		func(sortMenu *tview.Table) {
+			row, _ := sortMenu.GetSelection()
+
+			setSort(sortMenu, row, 0)
+		}, nil,
+		func(sortMenu *tview.Table) (int, int) {
+			var width, index int
+
+			sortMenu.SetSelectorWrap(true)
+
+			for row, sortKey := range deviceSortKeys {
+				if sortKey.Name == getSortKey() {
+					index = row
+				}
+
+				if len(sortKey.Title) > width {
+					width = len(sortKey.Title)
+				}
+
+				sortMenu.SetCell(row, 1, tview.NewTableCell(sortKey.Title).
+					SetExpansion(1).
+					SetReference(sortKey).
+					SetAlign(tview.AlignLeft).
+					SetOnClickedFunc(setSort).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(tcell.Style{}.
+						Foreground(theme.GetColor(theme.ThemeText)).
+						Background(theme.BackgroundColor(theme.ThemeText)),
+					),
+				)
+			}
+
+			markSortKey(sortMenu)
+
+			return width - 16, index
+		},
+	)
+}
+
+// setSort sets the selected sort key.
+func setSort(sortMenu *tview.Table, row, column int) {
+	cell := sortMenu.GetCell(row, 1)
+	if cell == nil {
+		return
+	}
+
+	sortKey, ok := cell.GetReference().(DeviceSort)
+	if !ok {
+		return
+	}
+
+	if err := cmd.SaveProperty("view.sort", sortKey.Name); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot save sort key"))
+	}
+
+	markSortKey(sortMenu)
+	refreshDeviceTable()
+}
+
+// markSortKey marks the selected sort key in the sort menu.
+func markSortKey(sortMenu *tview.Table) {
+	for row := 0; row < sortMenu.GetRowCount(); row++ {
+		cell := sortMenu.GetCell(row, 1)
+		if cell == nil {
+			continue
+		}
+
+		sortKey, ok := cell.GetReference().(DeviceSort)
+		if !ok {
+			continue
+		}
+
+		setMenuMarker(sortMenu, row, sortKey.Name == getSortKey())
+	}
+}
+
+// setMenuMarker sets or clears the marker of the specified row in a menu.
+func setMenuMarker(table *tview.Table, row int, marked bool) {
+	var marker string
+
+	if marked {
+		marker = string('•')
+	}
+
+	table.SetCell(row, 0, tview.NewTableCell(marker).
+		SetSelectable(false).
+		SetTextColor(theme.GetColor(theme.ThemeText)).
+		SetSelectedStyle(tcell.Style{}.
+			Foreground(theme.GetColor(theme.ThemeText)).
+			Background(theme.BackgroundColor(theme.ThemeText)),
+		),
+	)
+}
+
+// refreshDeviceTable redraws the device table with the current
+// search text, filters and sort key, and retains the selected device.
+func refreshDeviceTable() {
+	var row int
+
+	selected := getDeviceFromSelection(false)
+
+	DeviceTable.Clear()
+	for _, adapter := range shownAdapters() {
+		if isMultiAdapterView() {
+			setAdapterTableHeader(row, adapter)
+			row++
+		}
+
+		for _, device := range viewDevices(UI.Bluez.GetAdapterDevices(adapter.Path)) {
+			setDeviceTableInfo(row, device)
+			row++
+		}
+	}
+
+	row, ok := checkDeviceTable(selected.Path)
+	if !ok {
+		row = firstDeviceRow()
+	}
+
+	DeviceTable.Select(row, 0)
+}
+
+// viewDevices returns the devices which match the search text and filters,
+// sorted by the selected sort key.
+func viewDevices(devices []bluez.Device) []bluez.Device {
+	var visible []bluez.Device
+
+	for _, device := range devices {
+		if isDeviceVisible(device) {
+			visible = append(visible, device)
+		}
+	}
+
+	sort.SliceStable(visible, func(i, j int) bool {
+		return deviceLess(visible[i], visible[j])
+	})
+
+	return visible
+}
+
+// isDeviceVisible returns whether the device matches the search text and filters.
+func isDeviceVisible(device bluez.Device) bool {
+	switch {
+	case isFilterEnabled("paired") && !device.Paired,
+		isFilterEnabled("connected") && !device.Connected,
+		isFilterEnabled("hide-unnamed") && device.Name == "":
+		return false
+	}
+
+	if deviceType := cmd.GetProperty("view.filter.type"); deviceType != "" && device.Type != deviceType {
+		return false
+	}
+
+	search := strings.ToLower(getDeviceSearch())
+	if search == "" {
+		return true
+	}
+
+	for _, text := range []string{device.Name, device.Alias, device.Address} {
+		if strings.Contains(strings.ToLower(text), search) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deviceLess returns whether the first device should be listed
+// before the second device, according to the selected sort key.
+func deviceLess(first, second bluez.Device) bool {
+	switch getSortKey() {
+	case "name":
+		return strings.ToLower(deviceName(first)) < strings.ToLower(deviceName(second))
+
+	case "rssi":
+		if first.RSSI == 0 || second.RSSI == 0 {
+			return first.RSSI != 0
+		}
+
+		return first.RSSI > second.RSSI
+
+	case "last-seen":
+		deviceView.lock.Lock()
+		defer deviceView.lock.Unlock()
+
+		return deviceView.lastSeen[first.Path].After(deviceView.lastSeen[second.Path])
+
+	case "battery":
+		return first.Percentage > second.Percentage
+	}
+
+	return false
+}
+
+// deviceTableRow returns the row in the device table where the device
+// should be placed, according to the selected sort key.
+func deviceTableRow(device bluez.Device) int {
+	rows := DeviceTable.GetRowCount()
+
+	for row := 0; row < rows; row++ {
+		cell := DeviceTable.GetCell(row, 0)
+		if cell == nil {
+			continue
+		}
+
+		ref, ok := cell.GetReference().(bluez.Device)
+		if !ok {
+			continue
+		}
+
+		if deviceLess(device, ref) {
+			return row
+		}
+	}
+
+	return rows
+}
+
+// isDeviceSorted returns whether the device at the row is still placed
+// in order with its neighbouring devices, according to the selected sort key.
+func isDeviceSorted(row int, device bluez.Device) bool {
+	if getSortKey() == "" {
+		return true
+	}
+
+	deviceAt := func(row int) (bluez.Device, bool) {
+		cell := DeviceTable.GetCell(row, 0)
+		if cell == nil {
+			return bluez.Device{}, false
+		}
+
+		ref, ok := cell.GetReference().(bluez.Device)
+
+		return ref, ok
+	}
+
+	if previous, ok := deviceAt(row - 1); ok && deviceLess(device, previous) {
+		return false
+	}
+
+	if next, ok := deviceAt(row + 1); ok && deviceLess(next, device) {
+		return false
+	}
+
+	return true
+}
+
+// moveDeviceTableRow moves the device from the row to its sorted
+// position in the device table, and retains the selected device.
+func moveDeviceTableRow(row int, device bluez.Device) {
+	selected, _ := DeviceTable.GetSelection()
+
+	DeviceTable.RemoveRow(row)
+
+	newRow := deviceTableRow(device)
+	if newRow < DeviceTable.GetRowCount() {
+		DeviceTable.InsertRow(newRow)
+	}
+
+	setDeviceTableInfo(newRow, device)
+
+	switch {
+	case selected == row:
+		selected = newRow
+
+	case selected > row && selected <= newRow:
+		selected--
+
+	case selected < row && selected >= newRow:
+		selected++
+	}
+
+	DeviceTable.Select(selected, 0)
+}
+
+// updateDeviceTable adds, updates or removes the device from the device table,
+// based on whether the device matches the search text and filters.
+func updateDeviceTable(device bluez.Device) {
+	if !isAdapterShown(device.Adapter) {
+		return
+	}
+
+	row, exists := checkDeviceTable(device.Path)
+
+	switch visible := isDeviceVisible(device); {
+	case exists && visible && !isDeviceSorted(row, device):
+		if isMultiAdapterView() {
+			refreshDeviceTable()
+
+			break
+		}
+
+		moveDeviceTableRow(row, device)
+
+	case exists && visible:
+		setDeviceTableInfo(row, device)
+
+	case exists && !visible:
+		DeviceTable.RemoveRow(row)
+
+	case !exists && visible && isMultiAdapterView():
+		refreshDeviceTable()
+
+	case !exists && visible:
+		row = deviceTableRow(device)
+		if row < DeviceTable.GetRowCount() {
+			DeviceTable.InsertRow(row)
+		}
+
+		setDeviceTableInfo(row, device)
+	}
+}
+
+// markDeviceSeen records the time at which the device was last seen.
+func markDeviceSeen(device bluez.Device) {
+	deviceView.lock.Lock()
+	defer deviceView.lock.Unlock()
+
+	if deviceView.lastSeen == nil {
+		deviceView.lastSeen = make(map[string]time.Time)
+	}
+
+	deviceView.lastSeen[device.Path] = time.Now()
+}
+
+// setDeviceSearch sets the search text.
+func setDeviceSearch(search string) {
+	deviceView.lock.Lock()
+	defer deviceView.lock.Unlock()
+
+	deviceView.search = search
+}
+
+// getDeviceSearch returns the search text.
+func getDeviceSearch() string {
+	deviceView.lock.Lock()
+	defer deviceView.lock.Unlock()
+
+	return deviceView.search
+}
+
+// getSortKey returns the selected sort key.
+func getSortKey() string {
+	return cmd.GetProperty("view.sort")
+}
+
+// isFilterEnabled returns whether the specified filter is enabled.
+func isFilterEnabled(name string) bool {
+	return cmd.IsPropertyEnabled("view.filter." + name)
+}
+
+// deviceName returns the name of the device, or its address
+// if the device has no name.
+func deviceName(device bluez.Device) string {
+	if device.Name == "" {
+		return device.Address
+	}
+
+	return device.Name
+}
+
+// containsString returns whether the list contains the text.
+func containsString(list []string, text string) bool {
+	for _, item := range list {
+		if item == text {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+)
+
+// ConnectedProfiles stores the names of the connected profiles
+// of each device, mapped to the device's path.
+type ConnectedProfiles struct {
+	names map[string]string
+	lock  sync.Mutex
+}
+
+var connectedProfiles ConnectedProfiles
+
+// profileConnectMenu shows a popup to connect or disconnect
+// individual profiles of the selected device.
+func profileConnectMenu() {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	profiles, err := UI.Bluez.GetDeviceProfiles(device)
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot get device profiles"))
+		return
+	}
+	if len(profiles) == 0 {
+		InfoMessage("No profiles found for "+device.Name, false)
+		return
+	}
+
+	setContextMenu(
+		"device",
+		func(profileMenu *tview.Table) {
+			row, _ := profileMenu.GetSelection()
+
+			toggleDeviceProfile(profileMenu, row, 0)
+		}, nil,
+		func(profileMenu *tview.Table) (int, int) {
+			var width int
+
+			profileMenu.SetSelectorWrap(true)
+
+			for row, profile := range profiles {
+				if len(profile.Name) > width {
+					width = len(profile.Name)
+				}
+
+				profileMenu.SetCell(row, 1, tview.NewTableCell(profile.Name).
+					SetExpansion(1).
+					SetReference(profile).
+					SetAlign(tview.AlignLeft).
+					SetOnClickedFunc(toggleDeviceProfile).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(tcell.Style{}.
+						Foreground(theme.GetColor(theme.ThemeText)).
+						Background(theme.BackgroundColor(theme.ThemeText)),
+					),
+				)
+			}
+
+			markDeviceProfiles(profileMenu, profiles)
+
+			return width - 16, 0
+		},
+	)
+}
+
+// toggleDeviceProfile connects the selected profile, or disconnects it
+// if it is already connected.
+func toggleDeviceProfile(profileMenu *tview.Table, row, column int) {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	cell := profileMenu.GetCell(row, 1)
+	if cell == nil {
+		return
+	}
+
+	profile, ok := cell.GetReference().(bluez.DeviceProfile)
+	if !ok {
+		return
+	}
+
+	go func() {
+		var err error
+
+		if profile.State && profile.Connected {
+			err = disconnectDeviceProfile(device, profile)
+		} else {
+			InfoMessage("Connecting "+profile.Name+" on "+device.Name, true)
+
+			err = UI.Bluez.ConnectProfile(device.Path, profile.UUID)
+			switch {
+			case bluez.IsAlreadyConnected(err):
+				err = disconnectDeviceProfile(device, profile)
+
+			case err != nil:
+				err = errors.Wrap(err, "Cannot connect "+profile.Name)
+
+			default:
+				InfoMessage("Connected "+profile.Name+" on "+device.Name, false)
+			}
+		}
+		if err != nil {
+			ErrorMessage(err)
+		}
+
+		profiles, err := UI.Bluez.GetDeviceProfiles(UI.Bluez.GetDevice(device.Path))
+		if err != nil {
+			return
+		}
+
+		UI.QueueUpdateDraw(func() {
+			markDeviceProfiles(profileMenu, profiles)
+		})
+
+		refreshConnectedProfiles(device)
+	}()
+}
+
+// disconnectDeviceProfile disconnects the profile of the device.
+func disconnectDeviceProfile(device bluez.Device, profile bluez.DeviceProfile) error {
+	InfoMessage("Disconnecting "+profile.Name+" on "+device.Name, true)
+
+	if err := UI.Bluez.DisconnectProfile(device.Path, profile.UUID); err != nil {
+		return errors.Wrap(err, "Cannot disconnect "+profile.Name)
+	}
+
+	InfoMessage("Disconnected "+profile.Name+" on "+device.Name, false)
+
+	return nil
+}
+
+// markDeviceProfiles marks the connected profiles, and updates
+// the profile references with their current connection states.
+func markDeviceProfiles(profileMenu *tview.Table, profiles []bluez.DeviceProfile) {
+	states := make(map[string]bluez.DeviceProfile, len(profiles))
+	for _, profile := range profiles {
+		states[profile.UUID] = profile
+	}
+
+	for row := 0; row < profileMenu.GetRowCount(); row++ {
+		cell := profileMenu.GetCell(row, 1)
+		if cell == nil {
+			continue
+		}
+
+		profile, ok := cell.GetReference().(bluez.DeviceProfile)
+		if !ok {
+			continue
+		}
+
+		if state, ok := states[profile.UUID]; ok {
+			profile = state
+			cell.SetReference(profile)
+		}
+
+		setMenuMarker(profileMenu, row, profile.State && profile.Connected)
+	}
+}
+
+// connectedProfilesEvent refreshes the connected profiles of the device,
+// if its profile connection states were changed by the signal.
+func connectedProfilesEvent(signal *dbus.Signal) {
+	devicePath, ok := bluez.ProfileStateDevice(signal)
+	if !ok {
+		return
+	}
+
+	device := UI.Bluez.GetDevice(devicePath)
+	if device.Path == "" {
+		return
+	}
+
+	go refreshConnectedProfiles(device)
+}
+
+// refreshConnectedProfiles updates the stored connected profiles of the device,
+// and updates the device in the device table if they have changed.
+func refreshConnectedProfiles(device bluez.Device) {
+	if !isColumnEnabled("profile") {
+		return
+	}
+
+	var names []string
+
+	if device.Connected {
+		profiles, err := UI.Bluez.GetDeviceProfiles(device)
+		if err != nil {
+			return
+		}
+
+		for _, profile := range profiles {
+			if profile.State && profile.Connected && !containsString(names, profile.Name) {
+				names = append(names, profile.Name)
+			}
+		}
+	}
+
+	text := strings.Join(names, ", ")
+
+	connectedProfiles.lock.Lock()
+	if connectedProfiles.names == nil {
+		connectedProfiles.names = make(map[string]string)
+	}
+	changed := connectedProfiles.names[device.Path] != text
+	connectedProfiles.names[device.Path] = text
+	connectedProfiles.lock.Unlock()
+
+	if !changed {
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		if row, ok := checkDeviceTable(device.Path); ok {
+			setDeviceTableInfo(row, UI.Bluez.GetDevice(device.Path))
+		}
+	})
+}
+
+// getConnectedProfiles returns the names of the connected profiles of the device.
+func getConnectedProfiles(devicePath string) string {
+	connectedProfiles.lock.Lock()
+	defer connectedProfiles.lock.Unlock()
+
+	return connectedProfiles.names[devicePath]
+}
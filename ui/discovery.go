@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/tview"
+	"github.com/pkg/errors"
+)
+
+var discoveryTransports = []string{"auto", "bredr", "le"}
+
+// scanOptions shows a dialog to set the discovery filter of the current adapter.
+func scanOptions() {
+	adapter := UI.Bluez.GetCurrentAdapter()
+	filter := getDiscoveryFilter(adapter)
+
+	transport := 0
+	for i, t := range discoveryTransports {
+		if t == filter.Transport {
+			transport = i
+		}
+	}
+
+	var rssi, pathloss string
+	if filter.RSSI != 0 {
+		rssi = strconv.Itoa(int(filter.RSSI))
+	}
+	if filter.Pathloss != 0 {
+		pathloss = strconv.Itoa(int(filter.Pathloss))
+	}
+
+	form := tview.NewForm()
+	form.AddDropDown("Transport", discoveryTransports, transport, nil)
+	form.AddInputField("RSSI (dBm)", rssi, 10, tview.InputFieldInteger, nil)
+	form.AddInputField("Pathloss (dB)", pathloss, 10, tview.InputFieldInteger, nil)
+	form.AddInputField("UUIDs", strings.Join(filter.UUIDs, ","), 40, nil, nil)
+	form.AddInputField("Name/Address Prefix", filter.Pattern, 40, nil, nil)
+	form.AddCheckbox("Duplicate Data", filter.DuplicateData, nil)
+
+	modal := NewFormModal("scanoptions", "Scan Options", form, 19, 70)
+
+	form.AddButton("Apply", func() {
+		var newFilter bluez.DiscoveryFilter
+
+		_, newFilter.Transport = form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+
+		if value := form.GetFormItem(1).(*tview.InputField).GetText(); value != "" {
+			rssi, err := strconv.ParseInt(value, 10, 16)
+			if err != nil {
+				ErrorMessage(errors.New("Invalid RSSI value"))
+				return
+			}
+
+			newFilter.RSSI = int16(rssi)
+		}
+
+		if value := form.GetFormItem(2).(*tview.InputField).GetText(); value != "" {
+			pathloss, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				ErrorMessage(errors.New("Invalid Pathloss value"))
+				return
+			}
+
+			newFilter.Pathloss = uint16(pathloss)
+		}
+
+		for _, uuid := range strings.Split(form.GetFormItem(3).(*tview.InputField).GetText(), ",") {
+			if uuid = strings.TrimSpace(uuid); uuid != "" {
+				newFilter.UUIDs = append(newFilter.UUIDs, uuid)
+			}
+		}
+
+		newFilter.Pattern = form.GetFormItem(4).(*tview.InputField).GetText()
+		newFilter.DuplicateData = form.GetFormItem(5).(*tview.Checkbox).IsChecked()
+
+		modal.Exit(false)
+
+		go setDiscoveryFilter(adapter, newFilter)
+	})
+	form.AddButton("Clear", func() {
+		modal.Exit(false)
+
+		go setDiscoveryFilter(adapter, bluez.DiscoveryFilter{DuplicateData: true})
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit(false)
+	})
+
+	modal.Show()
+}
+
+// setDiscoveryFilter applies the discovery filter to the adapter, and saves it
+// as the last used filter for the adapter. The filter is saved using the adapter's
+// address, since the adapter ID can change across reboots or when it is replugged.
+func setDiscoveryFilter(adapter bluez.Adapter, filter bluez.DiscoveryFilter) {
+	if err := UI.Bluez.SetDiscoveryFilter(adapter.Path, filter); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot set discovery filter"))
+		return
+	}
+
+	err := cmd.SaveAdapterProperty(adapter.Address, "discovery-filter", map[string]interface{}{
+		"transport":      filter.Transport,
+		"rssi":           filter.RSSI,
+		"pathloss":       filter.Pathloss,
+		"uuids":          strings.Join(filter.UUIDs, ","),
+		"pattern":        filter.Pattern,
+		"duplicate-data": filter.DuplicateData,
+	})
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot save discovery filter"))
+		return
+	}
+
+	InfoMessage("Discovery filter set", false)
+}
+
+// applyDiscoveryFilter applies the last used discovery filter of the adapter.
+func applyDiscoveryFilter(adapter bluez.Adapter) error {
+	return UI.Bluez.SetDiscoveryFilter(adapter.Path, getDiscoveryFilter(adapter))
+}
+
+// getDiscoveryFilter returns the last used discovery filter of the adapter.
+func getDiscoveryFilter(adapter bluez.Adapter) bluez.DiscoveryFilter {
+	var filter bluez.DiscoveryFilter
+
+	property := func(name string) string {
+		return cmd.GetAdapterProperty(adapter.Address, "discovery-filter."+name)
+	}
+
+	filter.Transport = property("transport")
+	filter.Pattern = property("pattern")
+	filter.DuplicateData = property("duplicate-data") == "" ||
+		cmd.IsAdapterPropertyEnabled(adapter.Address, "discovery-filter.duplicate-data")
+
+	if rssi, err := strconv.ParseInt(property("rssi"), 10, 16); err == nil {
+		filter.RSSI = int16(rssi)
+	}
+
+	if pathloss, err := strconv.ParseUint(property("pathloss"), 10, 16); err == nil {
+		filter.Pathloss = uint16(pathloss)
+	}
+
+	for _, uuid := range strings.Split(property("uuids"), ",") {
+		if uuid != "" {
+			filter.UUIDs = append(filter.UUIDs, uuid)
+		}
+	}
+
+	return filter
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/network"
+	"github.com/darkhz/tview"
+	"github.com/pkg/errors"
+)
+
+// dunSettings shows a dialog to edit the dialup network settings of the device.
+// If onSave is provided, it is called after the settings are saved.
+func dunSettings(device bluez.Device, onSave func()) {
+	settings := network.GetDunSettings(device.Address)
+	if settings.Number == "" {
+		settings.Number = "*99#"
+	}
+
+	form := tview.NewForm()
+	form.AddInputField("APN", settings.APN, 30, nil, nil)
+	form.AddInputField("Number", settings.Number, 30, nil, nil)
+	form.AddInputField("Username", settings.Username, 30, nil, nil)
+	form.AddPasswordField("Password", settings.Password, 30, '*', nil)
+	form.AddPasswordField("PIN", settings.PIN, 10, '*', nil)
+
+	modal := NewFormModal("dunsettings", "Dialup Settings ("+device.Name+")", form, 19, 60)
+
+	form.AddButton("Save", func() {
+		text := func(index int) string {
+			return strings.TrimSpace(form.GetFormItem(index).(*tview.InputField).GetText())
+		}
+
+		settings := network.DunSettings{
+			APN:      text(0),
+			Number:   text(1),
+			Username: text(2),
+			Password: text(3),
+			PIN:      text(4),
+		}
+		if settings.Number == "" {
+			ErrorMessage(errors.New("Number is not set"))
+			return
+		}
+
+		if err := network.SaveDunSettings(device.Address, settings); err != nil {
+			ErrorMessage(errors.Wrap(err, "Cannot save dialup settings"))
+			return
+		}
+
+		modal.Exit(false)
+
+		if onSave != nil {
+			go onSave()
+			return
+		}
+
+		InfoMessage("Saved dialup settings for "+device.Name, false)
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit(false)
+	})
+
+	modal.Show()
+}
@@ -25,14 +25,38 @@ var functions = map[FunctionContext]map[cmd.Key]func(set ...string) bool{
 		cmd.KeyAdapterToggleDiscoverable: discoverable,
 		cmd.KeyAdapterTogglePairable:     pairable,
 		cmd.KeyAdapterToggleScan:         scan,
+		cmd.KeyAdapterScanOptions:        scanoptions,
+		cmd.KeyDeviceSearch:              search,
+		cmd.KeyDeviceFilter:              filter,
+		cmd.KeyDeviceSort:                sortdevices,
 		cmd.KeyAdapterChange:             change,
+		cmd.KeyAdapterRename:             renameadapter,
+		cmd.KeyAdapterInfo:               adapterinfo,
+		cmd.KeyAdapterToggleMultiView:    multiview,
+		cmd.KeyAdapterToggleAdvertise:    advertise,
+		cmd.KeyAdapterToggleNapServer:    napserver,
+		cmd.KeyAdapterNapClients:         napclients,
+		cmd.KeyAdapterNetworkProfiles:    networkprofiles,
+		cmd.KeyDeviceRename:              renamedevice,
+		cmd.KeyDeviceReconnect:           reconnect,
+		cmd.KeyDeviceProfileConnect:      profileconnect,
+		cmd.KeyDeviceGattExplorer:        gattexplorer,
+		cmd.KeyDeviceAdvertisement:       advertisement,
+		cmd.KeyDeviceSerialTerminal:      serialterminal,
 		cmd.KeyDeviceConnect:             connect,
 		cmd.KeyDevicePair:                pair,
 		cmd.KeyDeviceTrust:               trust,
 		cmd.KeyDeviceBlock:               block,
 		cmd.KeyDeviceSendFiles:           send,
 		cmd.KeyDeviceNetwork:             networkAP,
+		cmd.KeyDeviceNetworkStatus:       networkstatus,
 		cmd.KeyDeviceAudioProfiles:       profiles,
+		cmd.KeyDeviceAudioRoute:          routeaudio,
+		cmd.KeyDeviceAutoRouteAudio:      autorouteaudio,
+		cmd.KeyDeviceAutoSwitchProfile:   autoswitchprofile,
+		cmd.KeyDeviceVolumeUp:            volumeup,
+		cmd.KeyDeviceVolumeDown:          volumedown,
+		cmd.KeyDeviceVolumeMute:          mute,
 		cmd.KeyPlayerShow:                showplayer,
 		cmd.KeyDeviceInfo:                info,
 		cmd.KeyDeviceRemove:              remove,
@@ -44,15 +68,34 @@ var functions = map[FunctionContext]map[cmd.Key]func(set ...string) bool{
 		cmd.KeyAdapterTogglePower:        createPower,
 		cmd.KeyAdapterToggleDiscoverable: createDiscoverable,
 		cmd.KeyAdapterTogglePairable:     createPairable,
+		cmd.KeyAdapterToggleMultiView:    createMultiView,
+		cmd.KeyAdapterToggleAdvertise:    createAdvertise,
+		cmd.KeyAdapterToggleNapServer:    createNapServer,
 		cmd.KeyDeviceConnect:             createConnect,
 		cmd.KeyDeviceTrust:               createTrust,
 		cmd.KeyDeviceBlock:               createBlock,
+		cmd.KeyDeviceVolumeMute:          createMute,
+		cmd.KeyDeviceAutoRouteAudio:      createAutoRouteAudio,
+		cmd.KeyDeviceAutoSwitchProfile:   createAutoSwitchProfile,
 	},
 	FunctionVisible: {
-		cmd.KeyDeviceSendFiles:     visibleSend,
-		cmd.KeyDeviceNetwork:       visibleNetwork,
-		cmd.KeyDeviceAudioProfiles: visibleProfile,
-		cmd.KeyPlayerShow:          visiblePlayer,
+		cmd.KeyAdapterNapClients:       visibleNapClients,
+		cmd.KeyAdapterNetworkProfiles:  visibleNetworkProfiles,
+		cmd.KeyDeviceSendFiles:         visibleSend,
+		cmd.KeyDeviceNetwork:           visibleNetwork,
+		cmd.KeyDeviceNetworkStatus:     visibleNetwork,
+		cmd.KeyDeviceAudioProfiles:     visibleProfile,
+		cmd.KeyDeviceAudioRoute:        visibleRoute,
+		cmd.KeyDeviceAutoRouteAudio:    visibleProfile,
+		cmd.KeyDeviceAutoSwitchProfile: visibleProfile,
+		cmd.KeyDeviceVolumeUp:          visibleVolume,
+		cmd.KeyDeviceVolumeDown:        visibleVolume,
+		cmd.KeyDeviceVolumeMute:        visibleVolume,
+		cmd.KeyPlayerShow:              visiblePlayer,
+		cmd.KeyDeviceReconnect:         visibleReconnect,
+		cmd.KeyDeviceProfileConnect:    visibleProfileConnect,
+		cmd.KeyDeviceGattExplorer:      visibleGattExplorer,
+		cmd.KeyDeviceSerialTerminal:    visibleSerialTerminal,
 	},
 }
 
@@ -215,7 +258,8 @@ func pairable(set ...string) bool {
 
 // scan checks the current adapter's state and starts/stops discovery.
 func scan(set ...string) bool {
-	adapterPath := UI.Bluez.GetCurrentAdapter().Path
+	adapter := UI.Bluez.GetCurrentAdapter()
+	adapterPath := adapter.Path
 
 	props, err := UI.Bluez.GetAdapterProperties(adapterPath)
 	if err != nil {
@@ -239,6 +283,10 @@ func scan(set ...string) bool {
 	}
 
 	if !discover {
+		if err := applyDiscoveryFilter(adapter); err != nil {
+			ErrorMessage(err)
+		}
+
 		if err := UI.Bluez.StartDiscovery(adapterPath); err != nil {
 			ErrorMessage(err)
 			return false
@@ -257,6 +305,42 @@ func scan(set ...string) bool {
 	return true
 }
 
+// scanoptions launches a popup to set the discovery filter.
+func scanoptions(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		scanOptions()
+	})
+
+	return true
+}
+
+// search shows an input field to search for devices.
+func search(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		searchDevices()
+	})
+
+	return true
+}
+
+// filter launches a popup with the device filters.
+func filter(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		filterMenu()
+	})
+
+	return true
+}
+
+// sortdevices launches a popup with the device sort keys.
+func sortdevices(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		sortMenu()
+	})
+
+	return true
+}
+
 // change launches a popup with the adapters list.
 func change(set ...string) bool {
 	UI.QueueUpdateDraw(func() {
@@ -266,6 +350,105 @@ func change(set ...string) bool {
 	return true
 }
 
+// renameadapter launches a popup to rename the adapter.
+func renameadapter(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		renameAdapter()
+	})
+
+	return true
+}
+
+// multiview toggles between showing the devices of the current adapter
+// and the devices of all adapters.
+func multiview(set ...string) bool {
+	return toggleMultiAdapterView()
+}
+
+// advertise toggles advertising from the current adapter.
+func advertise(set ...string) bool {
+	return toggleAdvertising()
+}
+
+// napserver toggles the NAP server on the current adapter.
+func napserver(set ...string) bool {
+	return toggleNapServer()
+}
+
+// networkprofiles shows the network connection profiles.
+func networkprofiles(set ...string) bool {
+	showNetworkProfiles()
+
+	return true
+}
+
+// napclients shows the clients connected to the NAP server.
+func napclients(set ...string) bool {
+	napClients()
+
+	return true
+}
+
+// adapterinfo shows information about the adapter.
+func adapterinfo(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		getAdapterInfo()
+	})
+
+	return true
+}
+
+// reconnect launches a popup with the auto-reconnect policies.
+func reconnect(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		reconnectMenu()
+	})
+
+	return true
+}
+
+// profileconnect launches a popup to connect or disconnect
+// individual profiles of the selected device.
+func profileconnect(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		profileConnectMenu()
+	})
+
+	return true
+}
+
+// gattexplorer shows the GATT explorer for the selected device.
+func gattexplorer(set ...string) bool {
+	showGattExplorer()
+
+	return true
+}
+
+// advertisement shows the advertising data of the selected device.
+func advertisement(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		showAdvertisement()
+	})
+
+	return true
+}
+
+// serialterminal shows the serial terminal for the device.
+func serialterminal(set ...string) bool {
+	showSerialTerminal()
+
+	return true
+}
+
+// renamedevice launches a popup to rename the selected device.
+func renamedevice(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		renameDevice()
+	})
+
+	return true
+}
+
 // progress displays the progress view.
 func progress(set ...string) bool {
 	UI.QueueUpdateDraw(func() {
@@ -344,6 +527,23 @@ func createPairable(set ...string) bool {
 	return pairable
 }
 
+// createAdvertise sets the oncreate handler for the advertise submenu option.
+func createAdvertise(set ...string) bool {
+	return UI.Bluez.IsAdvertising(UI.Bluez.GetCurrentAdapter().Path)
+}
+
+// createNapServer sets the oncreate handler for the share internet submenu option.
+func createNapServer(set ...string) bool {
+	_, ok := getNapServer(UI.Bluez.GetCurrentAdapter().Path)
+
+	return ok
+}
+
+// createMultiView sets the oncreate handler for the all adapters submenu option.
+func createMultiView(set ...string) bool {
+	return isMultiAdapterView()
+}
+
 // createConnect sets the oncreate handler for the connect submenu option.
 func createConnect(set ...string) bool {
 	device := getDeviceFromSelection(false)
@@ -354,6 +554,26 @@ func createConnect(set ...string) bool {
 	return device.Connected
 }
 
+// createAutoRouteAudio sets the oncreate handler for the auto route audio submenu option.
+func createAutoRouteAudio(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	return cmd.IsDevicePropertyEnabled(device.Address, "audio-route")
+}
+
+// createAutoSwitchProfile sets the oncreate handler for the auto switch profile submenu option.
+func createAutoSwitchProfile(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	return cmd.IsDevicePropertyEnabled(device.Address, "auto-switch-profile")
+}
+
 // createTrust sets the oncreate handler for the trust submenu option.
 func createTrust(set ...string) bool {
 	device := getDeviceFromSelection(false)
@@ -374,6 +594,18 @@ func createBlock(set ...string) bool {
 	return device.Blocked
 }
 
+// createMute sets the oncreate handler for the mute submenu option.
+func createMute(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	sink, _ := getDeviceSink(device.Address)
+
+	return sink.Mute
+}
+
 // visibleSend sets the visible handler for the send submenu option.
 func visibleSend(set ...string) bool {
 	device := getDeviceFromSelection(false)
@@ -385,6 +617,28 @@ func visibleSend(set ...string) bool {
 		device.HaveService(bluez.OBEX_OBJPUSH_SVCLASS_ID)
 }
 
+// visibleNetworkProfiles sets the visible handler for the network profiles submenu option.
+func visibleNetworkProfiles(set ...string) bool {
+	return cmd.IsPropertyEnabled("network") && networkManager() != nil
+}
+
+// visibleNapClients sets the visible handler for the NAP clients submenu option.
+func visibleNapClients(set ...string) bool {
+	_, ok := getNapServer(UI.Bluez.GetCurrentAdapter().Path)
+
+	return ok
+}
+
+// visibleSerialTerminal sets the visible handler for the serial terminal submenu option.
+func visibleSerialTerminal(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	return device.Paired && device.HaveService(bluez.SERIAL_PORT_SVCLASS_ID)
+}
+
 // visibleNetwork sets the visible handler for the network submenu option.
 func visibleNetwork(set ...string) bool {
 	device := getDeviceFromSelection(false)
@@ -409,6 +663,49 @@ func visibleProfile(set ...string) bool {
 		device.HaveService(bluez.AUDIO_SINK_SVCLASS_ID)
 }
 
+// visibleRoute sets the visible handler for the route audio submenu option.
+func visibleRoute(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	return device.Connected && visibleProfile()
+}
+
+// visibleReconnect sets the visible handler for the auto reconnect submenu option.
+func visibleReconnect(set ...string) bool {
+	device := getDeviceFromSelection(false)
+
+	return device.Paired
+}
+
+// visibleProfileConnect sets the visible handler for the profile connect submenu option.
+func visibleProfileConnect(set ...string) bool {
+	device := getDeviceFromSelection(false)
+
+	return device.Paired && len(device.UUIDs) > 0
+}
+
+// visibleGattExplorer sets the visible handler for the GATT explorer submenu option.
+func visibleGattExplorer(set ...string) bool {
+	device := getDeviceFromSelection(false)
+
+	return device.Connected
+}
+
+// visibleVolume sets the visible handler for the volume submenu options.
+func visibleVolume(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" || !device.Connected {
+		return false
+	}
+
+	_, ok := getDeviceSink(device.Address)
+
+	return ok
+}
+
 // visiblePlayer sets the visible handler for the media player submenu option.
 func visiblePlayer(set ...string) bool {
 	device := getDeviceFromSelection(false)
@@ -465,6 +762,7 @@ func connect(set ...string) bool {
 		)
 	} else {
 		InfoMessage("Disconnecting from "+device.Name, true)
+		setManualDisconnect(device)
 		disconnectFunc()
 		InfoMessage("Disconnected from "+device.Name, false)
 	}
@@ -543,13 +841,17 @@ func block(set ...string) bool {
 // send gets a file list from the file picker and sends all selected files
 // to the target device.
 func send(set ...string) bool {
-	adapter := UI.Bluez.GetCurrentAdapter()
+	device := getDeviceFromSelection(true)
+	if device.Path == "" {
+		return false
+	}
+
+	adapter := getDeviceAdapter(device)
 	if !adapter.Lock.TryAcquire(1) {
 		return false
 	}
 	defer adapter.Lock.Release(1)
 
-	device := getDeviceFromSelection(true)
 	if !device.Paired || !device.Connected {
 		ErrorMessage(errors.New(device.Name + " is not paired and/or connected"))
 		return false
@@ -594,6 +896,15 @@ func send(set ...string) bool {
 	return true
 }
 
+// networkstatus shows the network connection status of the device.
+func networkstatus(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		showNetworkStatus()
+	})
+
+	return true
+}
+
 // networkAP launches a popup with the available networks.
 func networkAP(set ...string) bool {
 	UI.QueueUpdateDraw(func() {
@@ -612,6 +923,69 @@ func profiles(set ...string) bool {
 	return true
 }
 
+// routeaudio retrieves the selected device, and routes all audio to it.
+func routeaudio(set ...string) bool {
+	device := getDeviceFromSelection(true)
+	if device.Path == "" {
+		return false
+	}
+
+	return routeAudio(device)
+}
+
+// volumeup raises the sink volume of the selected device.
+func volumeup(set ...string) bool {
+	return changeVolume(true)
+}
+
+// volumedown lowers the sink volume of the selected device.
+func volumedown(set ...string) bool {
+	return changeVolume(false)
+}
+
+// mute toggles the sink mute state of the selected device.
+func mute(set ...string) bool {
+	return toggleMute()
+}
+
+// autorouteaudio retrieves the selected device, and toggles whether
+// audio is automatically routed to it when it connects.
+func autorouteaudio(set ...string) bool {
+	device := getDeviceFromSelection(true)
+	if device.Path == "" {
+		return false
+	}
+
+	enable := !cmd.IsDevicePropertyEnabled(device.Address, "audio-route")
+	if err := cmd.SaveDeviceProperty(device.Address, "audio-route", enable); err != nil {
+		ErrorMessage(errors.New("Cannot save audio route setting for " + device.Name))
+		return false
+	}
+
+	setMenuItemToggle("device", cmd.KeyDeviceAutoRouteAudio, enable)
+
+	return true
+}
+
+// autoswitchprofile retrieves the selected device, and toggles whether its
+// audio profile is switched to a headset profile when recording from it.
+func autoswitchprofile(set ...string) bool {
+	device := getDeviceFromSelection(true)
+	if device.Path == "" {
+		return false
+	}
+
+	enable := !cmd.IsDevicePropertyEnabled(device.Address, "auto-switch-profile")
+	if err := cmd.SaveDeviceProperty(device.Address, "auto-switch-profile", enable); err != nil {
+		ErrorMessage(errors.New("Cannot save profile switch setting for " + device.Name))
+		return false
+	}
+
+	setMenuItemToggle("device", cmd.KeyDeviceAutoSwitchProfile, enable)
+
+	return true
+}
+
 // showplayer starts the media player.
 func showplayer(set ...string) bool {
 	StartMediaPlayer()
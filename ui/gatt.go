@@ -0,0 +1,499 @@
+package ui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+)
+
+// GattExplorer describes the GATT service and characteristic explorer.
+type GattExplorer struct {
+	device bluez.Device
+	format int
+
+	table *tview.Table
+	log   *tview.TextView
+	title *tview.TextView
+	flex  *tview.Flex
+
+	notifying map[string]struct{}
+
+	lock sync.Mutex
+}
+
+// The different formats to display GATT values in.
+const (
+	gattFormatHex = iota
+	gattFormatUTF8
+	gattFormatDecoded
+)
+
+var (
+	gattExplorer GattExplorer
+
+	gattFormats = []string{"Hex", "UTF-8", "Decoded"}
+)
+
+// showGattExplorer displays the GATT services, characteristics and
+// descriptors of the selected device.
+func showGattExplorer() {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	services, err := UI.Bluez.GetGattServices(device.Path)
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot get GATT services"))
+		return
+	}
+	if len(services) == 0 {
+		InfoMessage("No GATT services found for "+device.Name, false)
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		gattExplorerView()
+
+		gattExplorer.lock.Lock()
+		gattExplorer.device = device
+		gattExplorer.notifying = make(map[string]struct{})
+		gattExplorer.lock.Unlock()
+
+		gattExplorer.log.Clear()
+		setGattExplorerTitle()
+		setGattTable(services)
+
+		UI.Pages.AddAndSwitchToPage("gattexplorer", gattExplorer.flex, true)
+	})
+}
+
+// gattExplorerView initializes the GATT explorer.
+func gattExplorerView() {
+	if gattExplorer.flex != nil {
+		return
+	}
+
+	gattExplorer.title = tview.NewTextView()
+	gattExplorer.title.SetDynamicColors(true)
+	gattExplorer.title.SetTextAlign(tview.AlignLeft)
+	gattExplorer.title.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	gattExplorer.table = tview.NewTable()
+	gattExplorer.table.SetSelectorWrap(true)
+	gattExplorer.table.SetSelectable(true, false)
+	gattExplorer.table.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	gattExplorer.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch cmd.KeyOperation(event, cmd.KeyContextGatt) {
+		case cmd.KeyClose:
+			closeGattExplorer()
+
+		case cmd.KeyGattRead:
+			row, ref := getGattSelection()
+			go readGattValue(row, ref)
+
+		case cmd.KeyGattWrite:
+			row, ref := getGattSelection()
+			go writeGattValue(row, ref)
+
+		case cmd.KeyGattToggleNotify:
+			row, ref := getGattSelection()
+			go toggleGattNotify(row, ref)
+
+		case cmd.KeyGattValueFormat:
+			gattExplorer.format = (gattExplorer.format + 1) % len(gattFormats)
+
+			setGattExplorerTitle()
+			refreshGattValues()
+
+		case cmd.KeyQuit:
+			go quit()
+		}
+
+		return ignoreDefaultEvent(event)
+	})
+
+	gattExplorer.log = tview.NewTextView()
+	gattExplorer.log.SetDynamicColors(true)
+	gattExplorer.log.SetScrollable(true)
+	gattExplorer.log.SetTextAlign(tview.AlignLeft)
+	gattExplorer.log.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	gattExplorer.log.SetTextColor(theme.GetColor(theme.ThemeText))
+
+	gattExplorer.flex = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(gattExplorer.title, 1, 0, false).
+		AddItem(gattExplorer.table, 0, 10, true).
+		AddItem(horizontalLine(), 1, 0, false).
+		AddItem(gattExplorer.log, 8, 0, false)
+}
+
+// closeGattExplorer stops the notifications started from the
+// GATT explorer, and closes it.
+func closeGattExplorer() {
+	gattExplorer.lock.Lock()
+	notifying := gattExplorer.notifying
+	gattExplorer.notifying = nil
+	gattExplorer.lock.Unlock()
+
+	go func() {
+		for path := range notifying {
+			UI.Bluez.StopNotify(path)
+		}
+	}()
+
+	UI.Pages.RemovePage("gattexplorer")
+	UI.Pages.SwitchToPage("main")
+}
+
+// setGattExplorerTitle sets the title of the GATT explorer.
+func setGattExplorerTitle() {
+	gattExplorer.title.SetText(
+		theme.ColorWrap(theme.ThemeText, "GATT Explorer: "+gattExplorer.device.Name, "::bu") +
+			theme.ColorWrap(theme.ThemeText, " (Values: "+gattFormats[gattExplorer.format]+")"),
+	)
+}
+
+// setGattTable lists the services, characteristics and descriptors
+// as a tree in the GATT explorer.
+func setGattTable(services []bluez.GattService) {
+	var row int
+
+	gattExplorer.table.Clear()
+
+	for _, service := range services {
+		name := bluez.GattName(service.UUID)
+		if service.Primary {
+			name += " (Primary)"
+		}
+
+		setGattTableRow(row, theme.ColorWrap(theme.ThemeText, name, "::b"), service.UUID, nil, nil, service)
+		row++
+
+		for _, characteristic := range service.Characteristics {
+			setGattTableRow(
+				row, "  "+bluez.GattName(characteristic.UUID),
+				characteristic.UUID, characteristic.Flags,
+				characteristic.Value, characteristic,
+			)
+			row++
+
+			for _, descriptor := range characteristic.Descriptors {
+				setGattTableRow(
+					row, "    "+bluez.GattName(descriptor.UUID),
+					descriptor.UUID, descriptor.Flags,
+					descriptor.Value, descriptor,
+				)
+				row++
+			}
+		}
+	}
+
+	gattExplorer.table.Select(0, 0)
+	gattExplorer.table.ScrollToBeginning()
+}
+
+// setGattTableRow writes the name, UUID, flags and value of a GATT
+// object into the specified row of the GATT explorer.
+func setGattTableRow(row int, name, uuid string, flags []string, value []byte, ref interface{}) {
+	flagText := strings.Join(flags, ",")
+	if _, ok := ref.(bluez.GattCharacteristic); ok && isGattNotifying(gattObjectPath(ref)) {
+		flagText += theme.ColorWrap(theme.ThemeDeviceConnected, " [notifying]")
+	}
+
+	gattExplorer.table.SetCell(row, 0, tview.NewTableCell(name).
+		SetExpansion(1).
+		SetReference(ref).
+		SetAlign(tview.AlignLeft).
+		SetTextColor(theme.GetColor(theme.ThemeText)).
+		SetSelectedStyle(tcell.Style{}.Bold(true)),
+	)
+	gattExplorer.table.SetCell(row, 1, tview.NewTableCell(uuid).
+		SetExpansion(1).
+		SetAlign(tview.AlignLeft).
+		SetTextColor(theme.GetColor(theme.ThemeText)).
+		SetSelectedStyle(tcell.Style{}.Bold(true)),
+	)
+	gattExplorer.table.SetCell(row, 2, tview.NewTableCell(flagText).
+		SetExpansion(1).
+		SetAlign(tview.AlignLeft).
+		SetTextColor(theme.GetColor(theme.ThemeText)).
+		SetSelectedStyle(tcell.Style{}.Bold(true)),
+	)
+	gattExplorer.table.SetCell(row, 3, tview.NewTableCell(formatGattValue(uuid, value)).
+		SetExpansion(1).
+		SetReference(value).
+		SetAlign(tview.AlignLeft).
+		SetTextColor(theme.GetColor(theme.ThemeText)).
+		SetSelectedStyle(tcell.Style{}.Bold(true)),
+	)
+}
+
+// readGattValue reads the value of the selected characteristic or descriptor.
+func readGattValue(row int, ref interface{}) {
+	var err error
+	var value []byte
+
+	switch object := ref.(type) {
+	case bluez.GattCharacteristic:
+		value, err = UI.Bluez.ReadCharacteristic(object.Path)
+
+	case bluez.GattDescriptor:
+		value, err = UI.Bluez.ReadDescriptor(object.Path)
+
+	default:
+		return
+	}
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot read value"))
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		updateGattValue(row, value, "Read")
+	})
+}
+
+// writeGattValue writes a value to the selected characteristic or descriptor.
+// Values prefixed with "0x" are parsed as hex bytes, and all other values
+// are written as UTF-8 text.
+func writeGattValue(row int, ref interface{}) {
+	var path string
+	var write func(path string, value []byte) error
+
+	switch object := ref.(type) {
+	case bluez.GattCharacteristic:
+		path, write = object.Path, UI.Bluez.WriteCharacteristic
+
+	case bluez.GattDescriptor:
+		path, write = object.Path, UI.Bluez.WriteDescriptor
+
+	default:
+		return
+	}
+
+	input := SetInput("Value (0x-prefixed hex or text):", struct{}{})
+	if input == "" {
+		return
+	}
+
+	value := []byte(input)
+	if strings.HasPrefix(input, "0x") {
+		decoded, err := hex.DecodeString(strings.ReplaceAll(input[2:], " ", ""))
+		if err != nil {
+			ErrorMessage(errors.Wrap(err, "Invalid hex value"))
+			return
+		}
+
+		value = decoded
+	}
+
+	if err := write(path, value); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot write value"))
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		updateGattValue(row, value, "Write")
+	})
+}
+
+// toggleGattNotify starts or stops notifications for the selected characteristic.
+func toggleGattNotify(row int, ref interface{}) {
+	characteristic, ok := ref.(bluez.GattCharacteristic)
+	if !ok {
+		return
+	}
+
+	name := bluez.GattName(characteristic.UUID)
+	notifying := isGattNotifying(characteristic.Path)
+
+	if notifying {
+		if err := UI.Bluez.StopNotify(characteristic.Path); err != nil {
+			ErrorMessage(errors.Wrap(err, "Cannot stop notifications"))
+			return
+		}
+
+		InfoMessage("Stopped notifications for "+name, false)
+	} else {
+		if err := UI.Bluez.StartNotify(characteristic.Path); err != nil {
+			ErrorMessage(errors.Wrap(err, "Cannot start notifications"))
+			return
+		}
+
+		InfoMessage("Started notifications for "+name, false)
+	}
+
+	gattExplorer.lock.Lock()
+	if gattExplorer.notifying != nil {
+		if notifying {
+			delete(gattExplorer.notifying, characteristic.Path)
+		} else {
+			gattExplorer.notifying[characteristic.Path] = struct{}{}
+		}
+	}
+	gattExplorer.lock.Unlock()
+
+	UI.QueueUpdateDraw(func() {
+		value, _ := gattExplorer.table.GetCell(row, 3).GetReference().([]byte)
+
+		setGattTableRow(
+			row, gattExplorer.table.GetCell(row, 0).Text,
+			characteristic.UUID, characteristic.Flags,
+			value, characteristic,
+		)
+	})
+}
+
+// gattEvent handles value changes of the characteristics, which are
+// shown in the GATT explorer. Only the value changes of characteristics
+// for which notifications were started are logged as notifications.
+func gattEvent(signal *dbus.Signal, signalData interface{}) {
+	gattValue, ok := signalData.(bluez.GattValue)
+	if !ok {
+		return
+	}
+
+	notifying := isGattNotifying(gattValue.Path)
+
+	UI.QueueUpdateDraw(func() {
+		if page, _ := UI.Pages.GetFrontPage(); page != "gattexplorer" {
+			return
+		}
+
+		for row := 0; row < gattExplorer.table.GetRowCount(); row++ {
+			if gattObjectPath(gattExplorer.table.GetCell(row, 0).GetReference()) == gattValue.Path {
+				if notifying {
+					updateGattValue(row, gattValue.Value, "Notify")
+				} else {
+					setGattValue(row, gattValue.Value)
+				}
+
+				return
+			}
+		}
+	})
+}
+
+// updateGattValue updates the value shown in the specified row
+// of the GATT explorer, and logs it.
+func updateGattValue(row int, value []byte, action string) {
+	uuid := setGattValue(row, value)
+
+	fmt.Fprintf(
+		gattExplorer.log, "%s %s %s: %s\n",
+		time.Now().Format("15:04:05"), action,
+		bluez.GattName(uuid), formatGattValue(uuid, value),
+	)
+	gattExplorer.log.ScrollToEnd()
+}
+
+// setGattValue updates the value shown in the specified row of the
+// GATT explorer, and returns the UUID of the GATT object in the row.
+func setGattValue(row int, value []byte) string {
+	uuid := gattObjectUUID(gattExplorer.table.GetCell(row, 0).GetReference())
+
+	gattExplorer.table.GetCell(row, 3).
+		SetReference(value).
+		SetText(formatGattValue(uuid, value))
+
+	return uuid
+}
+
+// refreshGattValues redraws all the values in the GATT explorer
+// with the current value format.
+func refreshGattValues() {
+	for row := 0; row < gattExplorer.table.GetRowCount(); row++ {
+		uuid := gattObjectUUID(gattExplorer.table.GetCell(row, 0).GetReference())
+		value, _ := gattExplorer.table.GetCell(row, 3).GetReference().([]byte)
+
+		gattExplorer.table.GetCell(row, 3).SetText(formatGattValue(uuid, value))
+	}
+}
+
+// formatGattValue formats the value according to the current value format.
+func formatGattValue(uuid string, value []byte) string {
+	if value == nil {
+		return ""
+	}
+
+	switch gattExplorer.format {
+	case gattFormatUTF8:
+		if utf8.Valid(value) {
+			return tview.Escape(string(value))
+		}
+
+	case gattFormatDecoded:
+		if decoded, ok := bluez.DecodeGattValue(uuid, value); ok {
+			return tview.Escape(decoded)
+		}
+	}
+
+	return fmt.Sprintf("% X", value)
+}
+
+// getGattSelection returns the selected row and GATT object in the GATT explorer.
+func getGattSelection() (int, interface{}) {
+	row, _ := gattExplorer.table.GetSelection()
+
+	cell := gattExplorer.table.GetCell(row, 0)
+	if cell == nil {
+		return row, nil
+	}
+
+	return row, cell.GetReference()
+}
+
+// isGattNotifying returns whether notifications were started
+// for the characteristic from the GATT explorer.
+func isGattNotifying(path string) bool {
+	gattExplorer.lock.Lock()
+	defer gattExplorer.lock.Unlock()
+
+	_, ok := gattExplorer.notifying[path]
+
+	return ok
+}
+
+// gattObjectPath returns the object path of a GATT object.
+func gattObjectPath(ref interface{}) string {
+	switch object := ref.(type) {
+	case bluez.GattService:
+		return object.Path
+
+	case bluez.GattCharacteristic:
+		return object.Path
+
+	case bluez.GattDescriptor:
+		return object.Path
+	}
+
+	return ""
+}
+
+// gattObjectUUID returns the UUID of a GATT object.
+func gattObjectUUID(ref interface{}) string {
+	switch object := ref.(type) {
+	case bluez.GattService:
+		return object.UUID
+
+	case bluez.GattCharacteristic:
+		return object.UUID
+
+	case bluez.GattDescriptor:
+		return object.UUID
+	}
+
+	return ""
+}
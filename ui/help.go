@@ -34,13 +34,36 @@ var (
 			{"Discoverable", "Toggle discoverable state", []cmd.Key{cmd.KeyAdapterToggleDiscoverable}, false},
 			{"Pairable", "Toggle pairable state", []cmd.Key{cmd.KeyAdapterTogglePairable}, false},
 			{"Scan", "Toggle scan (discovery state)", []cmd.Key{cmd.KeyAdapterToggleScan}, true},
+			{"Scan Options", "Set discovery filter", []cmd.Key{cmd.KeyAdapterScanOptions}, false},
+			{"Search", "Search for devices", []cmd.Key{cmd.KeyDeviceSearch}, true},
+			{"Filter", "Filter the device list", []cmd.Key{cmd.KeyDeviceFilter}, false},
+			{"Sort", "Sort the device list", []cmd.Key{cmd.KeyDeviceSort}, false},
 			{"Adapter", "Change adapter", []cmd.Key{cmd.KeyAdapterChange}, true},
+			{"All Adapters", "Show devices of all adapters", []cmd.Key{cmd.KeyAdapterToggleMultiView}, false},
+			{"Advertise", "Start/Stop advertising from adapter", []cmd.Key{cmd.KeyAdapterToggleAdvertise}, false},
+			{"Share Internet", "Start/Stop NAP server on adapter", []cmd.Key{cmd.KeyAdapterToggleNapServer}, false},
+			{"NAP Clients", "Show clients connected to NAP server", []cmd.Key{cmd.KeyAdapterNapClients}, false},
+			{"Network Profiles", "Manage network connection profiles", []cmd.Key{cmd.KeyAdapterNetworkProfiles}, false},
+			{"Rename Adapter", "Set alias of adapter", []cmd.Key{cmd.KeyAdapterRename}, false},
+			{"Adapter Info", "Show adapter information and set timeouts", []cmd.Key{cmd.KeyAdapterInfo}, false},
 			{"Send", "Send files", []cmd.Key{cmd.KeyDeviceSendFiles}, true},
 			{"Network", "Connect to network", []cmd.Key{cmd.KeyDeviceNetwork}, false},
+			{"Network Status", "Show network connection status", []cmd.Key{cmd.KeyDeviceNetworkStatus}, false},
+			{"Route Audio", "Route all audio to selected device", []cmd.Key{cmd.KeyDeviceAudioRoute}, false},
+			{"Auto Route Audio", "Route audio to selected device on connect", []cmd.Key{cmd.KeyDeviceAutoRouteAudio}, false},
+			{"Auto Switch Profile", "Switch to headset profile when recording from selected device", []cmd.Key{cmd.KeyDeviceAutoSwitchProfile}, false},
+			{"Volume", "Raise/Lower volume of selected device", []cmd.Key{cmd.KeyDeviceVolumeUp, cmd.KeyDeviceVolumeDown}, false},
+			{"Mute", "Toggle mute of selected device", []cmd.Key{cmd.KeyDeviceVolumeMute}, false},
 			{"Progress", "Progress view", []cmd.Key{cmd.KeyProgressView}, false},
 			{"Player", "Show/Hide player", []cmd.Key{cmd.KeyPlayerShow, cmd.KeyPlayerHide}, false},
 			{"Device Info", "Show device information", []cmd.Key{cmd.KeyDeviceInfo}, false},
+			{"GATT Explorer", "Browse GATT services of device", []cmd.Key{cmd.KeyDeviceGattExplorer}, false},
+			{"Advertisement", "Show advertising data of device", []cmd.Key{cmd.KeyDeviceAdvertisement}, false},
+			{"Serial Terminal", "Open serial terminal to device", []cmd.Key{cmd.KeyDeviceSerialTerminal}, false},
+			{"Rename", "Set alias of device", []cmd.Key{cmd.KeyDeviceRename}, false},
+			{"Auto Reconnect", "Set auto-reconnect policy of device", []cmd.Key{cmd.KeyDeviceReconnect}, false},
 			{"Connect", "Toggle connection with selected device", []cmd.Key{cmd.KeyDeviceConnect}, true},
+			{"Connect Profile", "Connect/Disconnect individual profiles of device", []cmd.Key{cmd.KeyDeviceProfileConnect}, false},
 			{"Pair", "Toggle pair with selected device", []cmd.Key{cmd.KeyDevicePair}, true},
 			{"Trust", "Toggle trust with selected device", []cmd.Key{cmd.KeyDeviceTrust}, false},
 			{"Remove", "Remove device from adapter", []cmd.Key{cmd.KeyDeviceRemove}, false},
@@ -66,6 +89,21 @@ var (
 			{"Cancel", "Cancel transfer", []cmd.Key{cmd.KeyProgressTransferCancel}, true},
 			{"Exit", "Exit", []cmd.Key{cmd.KeyClose}, true},
 		},
+		"GATT Explorer": {
+			{"Navigation", "Navigate between services and characteristics", []cmd.Key{cmd.KeyNavigateUp, cmd.KeyNavigateDown}, true},
+			{"Read", "Read value", []cmd.Key{cmd.KeyGattRead}, true},
+			{"Write", "Write value", []cmd.Key{cmd.KeyGattWrite}, true},
+			{"Notify", "Toggle notifications", []cmd.Key{cmd.KeyGattToggleNotify}, true},
+			{"Format", "Switch value format", []cmd.Key{cmd.KeyGattValueFormat}, true},
+			{"Exit", "Exit", []cmd.Key{cmd.KeyClose}, true},
+		},
+		"Serial Terminal": {
+			{"Echo", "Toggle local echo", []cmd.Key{cmd.KeySerialToggleEcho}, true},
+			{"Line Ending", "Switch line ending", []cmd.Key{cmd.KeySerialLineEnding}, true},
+			{"Hex", "Toggle hex view", []cmd.Key{cmd.KeySerialHexView}, true},
+			{"Log", "Start/Stop logging to file", []cmd.Key{cmd.KeySerialToggleLog}, true},
+			{"Exit", "Exit", []cmd.Key{cmd.KeyClose}, true},
+		},
 		"Media Player": {
 			{"Play/Pause", "Toggle play/pause", []cmd.Key{cmd.KeyNavigateUp, cmd.KeyNavigateDown}, false},
 			{"Next", "Next", []cmd.Key{cmd.KeyPlayerNext}, false},
@@ -104,9 +142,11 @@ func showStatusHelp(page string) {
 
 	help.page = UI.page
 	pages := map[string]string{
-		"main":         "Device Screen",
-		"filepicker":   "File Picker",
-		"progressview": "Progress View",
+		"main":           "Device Screen",
+		"filepicker":     "File Picker",
+		"progressview":   "Progress View",
+		"gattexplorer":   "GATT Explorer",
+		"serialterminal": "Serial Terminal",
 	}
 
 	items, ok := HelpTopics[pages[page]]
@@ -67,10 +67,62 @@ var (
 				Disabled: "Stop Scan",
 				OnClick:  true,
 			},
+			{
+				Key:     cmd.KeyAdapterScanOptions,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceSearch,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceFilter,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceSort,
+				OnClick: true,
+			},
 			{
 				Key:     cmd.KeyAdapterChange,
 				OnClick: true,
 			},
+			{
+				Key:     cmd.KeyAdapterRename,
+				OnClick: true,
+			},
+			{
+				Key:      cmd.KeyAdapterToggleMultiView,
+				Disabled: "Current Adapter",
+				OnClick:  true,
+				OnCreate: true,
+			},
+			{
+				Key:      cmd.KeyAdapterToggleAdvertise,
+				Disabled: "Stop Advertising",
+				OnClick:  true,
+				OnCreate: true,
+			},
+			{
+				Key:      cmd.KeyAdapterToggleNapServer,
+				Disabled: "Stop Sharing Internet",
+				OnClick:  true,
+				OnCreate: true,
+			},
+			{
+				Key:     cmd.KeyAdapterNapClients,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:     cmd.KeyAdapterNetworkProfiles,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:     cmd.KeyAdapterInfo,
+				OnClick: true,
+			},
 			{
 				Key:     cmd.KeyProgressView,
 				OnClick: true,
@@ -117,11 +169,52 @@ var (
 				OnClick: true,
 				Visible: true,
 			},
+			{
+				Key:     cmd.KeyDeviceNetworkStatus,
+				OnClick: true,
+				Visible: true,
+			},
 			{
 				Key:     cmd.KeyDeviceAudioProfiles,
 				OnClick: true,
 				Visible: true,
 			},
+			{
+				Key:     cmd.KeyDeviceAudioRoute,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:      cmd.KeyDeviceAutoRouteAudio,
+				Disabled: "Disable Auto Route Audio",
+				OnClick:  true,
+				OnCreate: true,
+				Visible:  true,
+			},
+			{
+				Key:      cmd.KeyDeviceAutoSwitchProfile,
+				Disabled: "Disable Auto Switch Profile",
+				OnClick:  true,
+				OnCreate: true,
+				Visible:  true,
+			},
+			{
+				Key:     cmd.KeyDeviceVolumeUp,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:     cmd.KeyDeviceVolumeDown,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:      cmd.KeyDeviceVolumeMute,
+				Disabled: "Unmute",
+				OnClick:  true,
+				OnCreate: true,
+				Visible:  true,
+			},
 			{
 				Key:     cmd.KeyPlayerShow,
 				OnClick: true,
@@ -131,6 +224,34 @@ var (
 				Key:     cmd.KeyDeviceInfo,
 				OnClick: true,
 			},
+			{
+				Key:     cmd.KeyDeviceRename,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceReconnect,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:     cmd.KeyDeviceProfileConnect,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:     cmd.KeyDeviceGattExplorer,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:     cmd.KeyDeviceAdvertisement,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceSerialTerminal,
+				OnClick: true,
+				Visible: true,
+			},
 			{
 				Key:     cmd.KeyDeviceRemove,
 				OnClick: true,
@@ -24,7 +24,13 @@ type Modal struct {
 	button *tview.TextView
 }
 
-var modals []*Modal
+var (
+	modals []*Modal
+
+	// confirmReplies stores the reply handlers of the displayed
+	// confirmation modals.
+	confirmReplies = make(map[string]func(reply string))
+)
 
 // NewModal returns a modal. If a primitive is not provided,
 // a table is attach to it.
@@ -104,6 +110,27 @@ func NewModal(name, title string, item tview.Primitive, height, width int) *Moda
 	return modal
 }
 
+// NewFormModal returns a modal with the provided form attached to it.
+func NewFormModal(name, title string, form *tview.Form, height, width int) *Modal {
+	var modal *Modal
+
+	form.SetItemPadding(1)
+	form.SetButtonsAlign(tview.AlignCenter)
+	form.SetLabelColor(theme.GetColor(theme.ThemeText))
+	form.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	form.SetFieldTextColor(theme.BackgroundColor(theme.ThemeText))
+	form.SetFieldBackgroundColor(theme.GetColor(theme.ThemeText))
+	form.SetButtonTextColor(theme.BackgroundColor(theme.ThemeText))
+	form.SetButtonBackgroundColor(theme.GetColor(theme.ThemeText))
+	form.SetCancelFunc(func() {
+		modal.Exit(false)
+	})
+
+	modal = NewModal(name, title, form, height, width)
+
+	return modal
+}
+
 // NewMenuModal returns a menu modal.
 func NewMenuModal(name string, regionX, regionY int) *Modal {
 	table := tview.NewTable()
@@ -167,6 +194,7 @@ func NewConfirmModal(name, title, message string) string {
 	reply := make(chan string, 10)
 
 	send := func(msg string) {
+		delete(confirmReplies, name)
 		modal.Exit(false)
 
 		select {
@@ -233,12 +261,23 @@ func NewConfirmModal(name, title, message string) string {
 			m.Exit(false)
 		}
 
+		confirmReplies[name] = send
+
 		modal.Show()
 	})
 
 	return <-reply
 }
 
+// AnswerConfirmModal answers the confirmation modal with the provided name.
+func AnswerConfirmModal(name, reply string) {
+	UI.QueueUpdateDraw(func() {
+		if send, ok := confirmReplies[name]; ok {
+			send(reply)
+		}
+	})
+}
+
 // Show shows the modal.
 func (m *Modal) Show() {
 	var x, y, xprop, xattach int
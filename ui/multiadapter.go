@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/pkg/errors"
+)
+
+// isMultiAdapterView returns whether the devices of all adapters
+// are shown in the device table.
+func isMultiAdapterView() bool {
+	return cmd.IsPropertyEnabled("view.all-adapters")
+}
+
+// isAdapterShown returns whether the devices of the adapter
+// are shown in the device table.
+func isAdapterShown(adapterPath string) bool {
+	return isMultiAdapterView() || adapterPath == UI.Bluez.GetCurrentAdapter().Path
+}
+
+// shownAdapters returns the adapters whose devices are shown
+// in the device table.
+func shownAdapters() []bluez.Adapter {
+	if isMultiAdapterView() {
+		return UI.Bluez.GetAdapters()
+	}
+
+	return []bluez.Adapter{UI.Bluez.GetCurrentAdapter()}
+}
+
+// toggleMultiAdapterView toggles between showing the devices
+// of the current adapter and the devices of all adapters.
+func toggleMultiAdapterView() bool {
+	enable := !isMultiAdapterView()
+
+	if err := cmd.SaveProperty("view.all-adapters", enable); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot save adapter view"))
+	}
+
+	UI.QueueUpdateDraw(func() {
+		refreshDeviceTable()
+	})
+
+	setMenuItemToggle("adapter", cmd.KeyAdapterToggleMultiView, enable)
+
+	return true
+}
+
+// setAdapterTableHeader writes the adapter's name and states into
+// the specified row of the DeviceTable.
+func setAdapterTableHeader(row int, adapter bluez.Adapter) {
+	name := adapter.Alias
+	if name == "" {
+		name = adapter.Name
+	}
+
+	header := theme.ColorWrap(
+		theme.ThemeAdapter,
+		name+" ("+bluez.GetAdapterID(adapter.Path)+")", "::bu",
+	)
+
+	var states []string
+	for _, state := range []struct {
+		Title   string
+		Enabled bool
+		Color   theme.ThemeContext
+	}{
+		{"Powered", adapter.Powered, theme.ThemeAdapterPowered},
+		{"Scanning", adapter.Discovering, theme.ThemeAdapterScanning},
+		{"Discoverable", adapter.Discoverable, theme.ThemeAdapterDiscoverable},
+		{"Pairable", adapter.Pairable, theme.ThemeAdapterPairable},
+	} {
+		if state.Enabled {
+			states = append(states, theme.ColorWrap(state.Color, state.Title))
+		}
+	}
+	if !adapter.Powered {
+		states = append(states, theme.ColorWrap("AdapterNotPowered", "Not Powered"))
+	}
+
+	DeviceTable.SetCell(
+		row, 0, tview.NewTableCell(header+" "+strings.Join(states, " ")).
+			SetExpansion(1).
+			SetReference(adapter).
+			SetSelectable(false).
+			SetAlign(tview.AlignLeft),
+	)
+}
+
+// updateAdapterTableHeader updates the header of the adapter
+// in the DeviceTable, if it is shown.
+func updateAdapterTableHeader(adapter bluez.Adapter) {
+	if row, ok := checkAdapterTable(adapter.Path); ok {
+		setAdapterTableHeader(row, adapter)
+	}
+}
+
+// checkAdapterTable iterates through the DeviceTable and checks
+// if a header for the adapter whose path matches the path parameter exists.
+func checkAdapterTable(path string) (int, bool) {
+	for row := 0; row < DeviceTable.GetRowCount(); row++ {
+		cell := DeviceTable.GetCell(row, 0)
+		if cell == nil {
+			continue
+		}
+
+		ref, ok := cell.GetReference().(bluez.Adapter)
+		if !ok {
+			continue
+		}
+
+		if ref.Path == path {
+			return row, true
+		}
+	}
+
+	return -1, false
+}
+
+// firstDeviceRow returns the first row in the DeviceTable that has a device.
+func firstDeviceRow() int {
+	for row := 0; row < DeviceTable.GetRowCount(); row++ {
+		cell := DeviceTable.GetCell(row, 0)
+		if cell == nil {
+			continue
+		}
+
+		if _, ok := cell.GetReference().(bluez.Device); ok {
+			return row
+		}
+	}
+
+	return 0
+}
+
+// getDeviceAdapter returns the adapter which owns the device. If the
+// adapter is not found, the current adapter is returned.
+func getDeviceAdapter(device bluez.Device) bluez.Adapter {
+	for _, adapter := range UI.Bluez.GetAdapters() {
+		if adapter.Path == device.Adapter {
+			return adapter
+		}
+	}
+
+	return UI.Bluez.GetCurrentAdapter()
+}
@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/network"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+)
+
+// NapServer describes a NAP server running on an adapter.
+type NapServer struct {
+	bridge string
+	shared bool
+}
+
+// napDefaultBridge is the bridge interface to which the NAP clients
+// are added, if no bridge interface is configured for the adapter.
+const napDefaultBridge = "pan0"
+
+var (
+	napServers     = make(map[string]NapServer)
+	napServersLock sync.Mutex
+)
+
+// toggleNapServer stops the NAP server of the current adapter if it is
+// running, otherwise it shows a dialog to start the NAP server.
+func toggleNapServer() bool {
+	adapter := UI.Bluez.GetCurrentAdapter()
+
+	if _, ok := getNapServer(adapter.Path); !ok {
+		UI.QueueUpdateDraw(func() {
+			napServerOptions(adapter)
+		})
+
+		return true
+	}
+
+	if err := stopNapServer(adapter); err != nil {
+		ErrorMessage(err)
+		return false
+	}
+
+	setMenuItemToggle("adapter", cmd.KeyAdapterToggleNapServer, false)
+	InfoMessage("Stopped sharing internet from "+bluez.GetAdapterID(adapter.Path), false)
+
+	return true
+}
+
+// napServerOptions shows a dialog to set the bridge interface,
+// and starts the NAP server on the adapter.
+func napServerOptions(adapter bluez.Adapter) {
+	adapterID := bluez.GetAdapterID(adapter.Path)
+
+	bridge := cmd.GetAdapterProperty(adapterID, "nap-bridge")
+	if bridge == "" {
+		bridge = napDefaultBridge
+	}
+
+	shared := networkManager() != nil &&
+		(cmd.GetAdapterProperty(adapterID, "nap-shared") == "" ||
+			cmd.IsAdapterPropertyEnabled(adapterID, "nap-shared"))
+
+	form := tview.NewForm()
+	form.AddInputField("Bridge Interface", bridge, 20, nil, nil)
+	form.AddCheckbox("Share via NetworkManager", shared, nil)
+
+	modal := NewFormModal("napserver", "Share Internet", form, 11, 60)
+
+	form.AddButton("Start", func() {
+		bridge := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		shared := form.GetFormItem(1).(*tview.Checkbox).IsChecked()
+
+		if bridge == "" {
+			ErrorMessage(errors.New("Bridge interface is not set"))
+			return
+		}
+		if shared && networkManager() == nil {
+			ErrorMessage(errors.New("NetworkManager is not available"))
+			return
+		}
+
+		modal.Exit(false)
+
+		go startNapServer(adapter, NapServer{bridge: bridge, shared: shared})
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit(false)
+	})
+
+	modal.Show()
+}
+
+// startNapServer starts the NAP server on the adapter. If the server is shared,
+// a NetworkManager bridge connection with internet sharing is activated first.
+func startNapServer(adapter bluez.Adapter, server NapServer) {
+	adapterID := bluez.GetAdapterID(adapter.Path)
+
+	InfoMessage("Starting NAP server on "+adapterID, true)
+
+	if server.shared {
+		if err := networkManager().StartSharedBridge(server.bridge); err != nil {
+			ErrorMessage(errors.Wrap(err, "Cannot activate bridge "+server.bridge))
+			return
+		}
+	}
+
+	if err := UI.Bluez.RegisterNetworkServer(adapter.Path, server.bridge); err != nil {
+		if server.shared {
+			networkManager().StopSharedBridge(server.bridge)
+		}
+
+		ErrorMessage(errors.Wrap(err, "Cannot start NAP server"))
+		return
+	}
+
+	napServersLock.Lock()
+	napServers[adapter.Path] = server
+	napServersLock.Unlock()
+
+	for property, value := range map[string]interface{}{
+		"nap-bridge": server.bridge,
+		"nap-shared": server.shared,
+	} {
+		if err := cmd.SaveAdapterProperty(adapterID, property, value); err != nil {
+			ErrorMessage(errors.Wrap(err, "Cannot save NAP server settings"))
+		}
+	}
+
+	setMenuItemToggle("adapter", cmd.KeyAdapterToggleNapServer, true)
+	InfoMessage("Sharing internet from "+adapterID+" via "+server.bridge, false)
+}
+
+// stopNapServer stops the NAP server on the adapter.
+func stopNapServer(adapter bluez.Adapter) error {
+	server, ok := getNapServer(adapter.Path)
+	if !ok {
+		return nil
+	}
+
+	if err := UI.Bluez.UnregisterNetworkServer(adapter.Path); err != nil {
+		return errors.Wrap(err, "Cannot stop NAP server")
+	}
+
+	napServersLock.Lock()
+	delete(napServers, adapter.Path)
+	napServersLock.Unlock()
+
+	if server.shared {
+		if err := networkManager().StopSharedBridge(server.bridge); err != nil {
+			return errors.Wrap(err, "Cannot deactivate bridge "+server.bridge)
+		}
+	}
+
+	return nil
+}
+
+// napClients shows the clients connected to the NAP server of the current adapter.
+func napClients() {
+	adapter := UI.Bluez.GetCurrentAdapter()
+
+	server, ok := getNapServer(adapter.Path)
+	if !ok {
+		return
+	}
+
+	clients, err := network.BridgeClients(server.bridge)
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot get NAP clients"))
+		return
+	}
+
+	interfaces := network.BridgeInterfaces(server.bridge)
+	if interfaces == nil {
+		interfaces = []string{"None"}
+	}
+
+	names := make([]string, len(clients))
+	for i, client := range clients {
+		names[i] = client.Address
+		if device, ok := UI.Bluez.GetDeviceFromAddress(client.Address); ok {
+			names[i] = device.Name
+		}
+	}
+
+	UI.QueueUpdateDraw(func() {
+		clientsModal := NewModal("napclients", "NAP Clients ("+server.bridge+")", nil, 20, 80)
+
+		setCell := func(row, col int, text string) {
+			clientsModal.Table.SetCell(row, col, tview.NewTableCell(text).
+				SetExpansion(1).
+				SetAlign(tview.AlignLeft).
+				SetTextColor(theme.GetColor(theme.ThemeText)).
+				SetSelectedStyle(tcell.Style{}.
+					Bold(true).
+					Underline(true),
+				),
+			)
+		}
+
+		setCell(0, 0, "[::b]Interfaces:")
+		setCell(0, 1, strings.Join(interfaces, ", "))
+		setCell(1, 0, "[::b]Clients:")
+
+		if clients == nil {
+			setCell(1, 1, "None")
+		}
+		for i, client := range clients {
+			setCell(i+1, 1, names[i])
+			setCell(i+1, 2, client.Address+" ("+client.Interface+")")
+		}
+
+		clientsModal.Height = clientsModal.Table.GetRowCount() + 4
+		clientsModal.Show()
+	})
+}
+
+// getNapServer returns the NAP server running on the adapter.
+func getNapServer(adapterPath string) (NapServer, bool) {
+	napServersLock.Lock()
+	defer napServersLock.Unlock()
+
+	server, ok := napServers[adapterPath]
+
+	return server, ok
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/network"
 	"github.com/darkhz/bluetuith/theme"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
@@ -25,10 +26,13 @@ func networkSelect() {
 			"Personal Area Network",
 		})
 	}
-	if device.HaveService(bluez.DIALUP_NET_SVCLASS_ID) {
+	if device.HaveService(bluez.DIALUP_NET_SVCLASS_ID) && networkManager() != nil {
 		connTypes = append(connTypes, []string{
 			"dun",
 			"Dialup Network",
+		}, []string{
+			"dun-settings",
+			"Dialup Settings",
 		})
 	}
 
@@ -52,6 +56,14 @@ func networkSelect() {
 				return
 			}
 
+			if connType == "dun-settings" {
+				go UI.QueueUpdateDraw(func() {
+					dunSettings(device, nil)
+				})
+
+				return
+			}
+
 			go networkConnect(device, connType)
 
 		}, nil,
@@ -76,6 +88,10 @@ func networkSelect() {
 						Background(theme.BackgroundColor(theme.ThemeText)),
 					),
 				)
+				if ctype == "dun-settings" {
+					continue
+				}
+
 				networkMenu.SetCell(row, 1, tview.NewTableCell("("+strings.ToUpper(ctype)+")").
 					SetAlign(tview.AlignRight).
 					SetTextColor(theme.GetColor(theme.ThemeText)).
@@ -92,7 +108,19 @@ func networkSelect() {
 }
 
 // networkConnect connects to the network with the selected network type.
+// If no dialup settings are stored for the device, the dialup settings
+// editor is shown before connecting via DUN.
 func networkConnect(device bluez.Device, connType string) {
+	if connType == "dun" && !network.HasDunSettings(device.Address) {
+		UI.QueueUpdateDraw(func() {
+			dunSettings(device, func() {
+				networkConnect(device, connType)
+			})
+		})
+
+		return
+	}
+
 	info := fmt.Sprintf("%s (%s)",
 		device.Name, strings.ToUpper(connType),
 	)
@@ -117,3 +145,10 @@ func networkConnect(device bluez.Device, connType string) {
 		},
 	)
 }
+
+// networkManager returns the network backend if it is the NetworkManager backend.
+func networkManager() *network.Network {
+	nm, _ := UI.Network.(*network.Network)
+
+	return nm
+}
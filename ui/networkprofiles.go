@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/bluetuith/network"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+)
+
+// profilesModal holds the network profile manager.
+var profilesModal *Modal
+
+// showNetworkProfiles shows the NetworkManager bluetooth connection profiles.
+func showNetworkProfiles() {
+	nm := networkManager()
+	if nm == nil {
+		ErrorMessage(errors.New("NetworkManager is not available"))
+		return
+	}
+
+	profiles, err := nm.GetProfiles()
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot get network profiles"))
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		if profilesModal == nil || !profilesModal.Open {
+			profilesModal = NewModal("networkprofiles", "Network Profiles", nil, 20, 100)
+			profilesModal.Table.SetSelectedFunc(func(row, col int) {
+				cell := profilesModal.Table.GetCell(row, 0)
+				if cell == nil {
+					return
+				}
+
+				switch ref := cell.GetReference().(type) {
+				case network.Profile:
+					editNetworkProfile(ref)
+
+				case []network.Profile:
+					go deleteNetworkProfiles(ref)
+				}
+			})
+		}
+
+		setProfilesTable(profiles)
+		profilesModal.Show()
+	})
+}
+
+// setProfilesTable writes the network profiles into the profile manager.
+func setProfilesTable(profiles []network.Profile) {
+	var removed []network.Profile
+
+	table := profilesModal.Table
+	row, _ := table.GetSelection()
+
+	table.Clear()
+
+	setCell := func(row, col int, text string) *tview.TableCell {
+		cell := tview.NewTableCell(text).
+			SetExpansion(1).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Bold(true).
+				Underline(true),
+			)
+
+		table.SetCell(row, col, cell)
+
+		return cell
+	}
+
+	if profiles == nil {
+		setCell(0, 0, "No network profiles")
+	} else {
+		for col, header := range []string{"Name", "Type", "Address", "Autoconnect", "IPv4", ""} {
+			setCell(0, col, "[::b]"+header).SetSelectable(false)
+		}
+	}
+
+	addresses := make(map[string]struct{})
+	for _, adapter := range UI.Bluez.GetAdapters() {
+		for _, device := range UI.Bluez.GetAdapterDevices(adapter.Path) {
+			addresses[device.Address] = struct{}{}
+		}
+	}
+
+	for i, profile := range profiles {
+		autoconnect := "no"
+		if profile.Autoconnect {
+			autoconnect = "yes"
+		}
+
+		status := ""
+		if _, ok := addresses[profile.Address]; !ok {
+			status = "(removed)"
+			removed = append(removed, profile)
+		}
+
+		setCell(i+1, 0, tview.Escape(profile.Name)).SetReference(profile)
+		setCell(i+1, 1, strings.ToUpper(profile.Type))
+		setCell(i+1, 2, profile.Address)
+		setCell(i+1, 3, autoconnect)
+		setCell(i+1, 4, profile.IPv4Method)
+		setCell(i+1, 5, status)
+	}
+
+	if removed != nil {
+		setCell(len(profiles)+2, 0, "[::b][Delete removed device profiles[]").SetReference(removed)
+	}
+
+	if row >= table.GetRowCount() {
+		row = table.GetRowCount() - 1
+	}
+	if row < 1 && profiles != nil {
+		row = 1
+	}
+	table.Select(row, 0)
+
+	if height := table.GetRowCount() + 4; profilesModal.Height != height {
+		profilesModal.Height = height
+		profilesModal.pageHeight = 0
+
+		ResizeModal()
+	}
+}
+
+// editNetworkProfile shows a dialog to edit or delete the network profile.
+// If the profile's IPv4 method is not one of the supported methods, it is
+// shown as the first option, so that it is kept when the profile is saved.
+func editNetworkProfile(profile network.Profile) {
+	method := -1
+	methods := network.IPv4Methods
+	for i, m := range methods {
+		if m == profile.IPv4Method {
+			method = i
+		}
+	}
+	if method < 0 {
+		method = 0
+		methods = append([]string{profile.IPv4Method}, methods...)
+	}
+
+	form := tview.NewForm()
+	form.AddCheckbox("Autoconnect", profile.Autoconnect, nil)
+	form.AddDropDown("IPv4 Method", methods, method, nil)
+	form.AddInputField("DNS", strings.Join(profile.DNS, ", "), 40, nil, nil)
+	form.AddInputField("Route Metric", strconv.FormatInt(profile.Metric, 10), 10, nil, nil)
+
+	modal := NewFormModal("editprofile", profile.Name, form, 17, 70)
+
+	form.AddButton("Save", func() {
+		metric, err := strconv.ParseInt(strings.TrimSpace(form.GetFormItem(3).(*tview.InputField).GetText()), 10, 64)
+		if err != nil || metric < -1 {
+			ErrorMessage(errors.New("Invalid route metric value"))
+			return
+		}
+
+		profile.Autoconnect = form.GetFormItem(0).(*tview.Checkbox).IsChecked()
+		_, profile.IPv4Method = form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		profile.Metric = metric
+
+		profile.DNS = nil
+		for _, address := range strings.Split(form.GetFormItem(2).(*tview.InputField).GetText(), ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				profile.DNS = append(profile.DNS, address)
+			}
+		}
+
+		modal.Exit(false)
+
+		go func() {
+			if err := networkManager().UpdateProfile(profile); err != nil {
+				ErrorMessage(errors.Wrap(err, "Cannot update network profile"))
+				return
+			}
+
+			InfoMessage("Updated network profile "+profile.Name, false)
+			showNetworkProfiles()
+		}()
+	})
+	form.AddButton("Delete", func() {
+		modal.Exit(false)
+
+		go deleteNetworkProfiles([]network.Profile{profile})
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit(false)
+	})
+
+	modal.Show()
+}
+
+// deleteNetworkProfiles deletes the provided network profiles.
+func deleteNetworkProfiles(profiles []network.Profile) {
+	name := profiles[0].Name
+	if len(profiles) > 1 {
+		name = strconv.Itoa(len(profiles)) + " profiles"
+	}
+
+	if txt := SetInput("Delete " + name + " (y/n)?"); txt != "y" {
+		return
+	}
+
+	for _, profile := range profiles {
+		if err := networkManager().DeleteProfile(profile.UUID); err != nil {
+			ErrorMessage(errors.Wrap(err, "Cannot delete network profile "+profile.Name))
+			return
+		}
+	}
+
+	InfoMessage("Deleted "+strconv.Itoa(len(profiles))+" network profile(s)", false)
+	showNetworkProfiles()
+}
@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/network"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+)
+
+// NetworkStatusPanel describes the network status panel of a device.
+type NetworkStatusPanel struct {
+	modal    *Modal
+	device   bluez.Device
+	connType string
+
+	exit chan struct{}
+	once sync.Once
+}
+
+// networkStatusInterval is the interval at which the network status is refreshed.
+const networkStatusInterval = 2 * time.Second
+
+// showNetworkStatus shows the network connection status of the selected device,
+// which is updated as the NetworkManager connection state changes.
+func showNetworkStatus() {
+	if UI.Network == nil {
+		ErrorMessage(errors.New("Network connection is disabled"))
+		return
+	}
+
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	panel := &NetworkStatusPanel{
+		device: device,
+		exit:   make(chan struct{}),
+	}
+	if !device.HaveService(bluez.PANU_SVCLASS_ID) {
+		panel.connType = "dun"
+	} else {
+		panel.connType = "panu"
+	}
+
+	panel.modal = NewModal("networkstatus", "Network Status", nil, 20, 80)
+	panel.modal.Table.SetSelectedFunc(func(row, col int) {
+		cell := panel.modal.Table.GetCell(row, 0)
+		if cell == nil {
+			return
+		}
+
+		action, ok := cell.GetReference().(string)
+		if !ok {
+			return
+		}
+
+		go panel.action(action)
+	})
+
+	panel.setTable(network.ConnectionStatus{State: "Fetching"})
+	panel.modal.Show()
+
+	go panel.watch()
+}
+
+// watch refreshes the network status panel whenever the connection
+// state changes, and periodically to update the byte counters.
+func (n *NetworkStatusPanel) watch() {
+	ticker := time.NewTicker(networkStatusInterval)
+	defer ticker.Stop()
+
+	changed := UI.Network.WatchState(n.exit)
+
+	for {
+		n.refresh()
+
+		select {
+		case <-n.exit:
+			return
+
+		case <-changed:
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh fetches the network status and updates the panel.
+func (n *NetworkStatusPanel) refresh() {
+	status, err := UI.Network.GetConnectionStatus(n.device.Address)
+	if err != nil {
+		status.State = "Error: " + err.Error()
+	}
+
+	UI.QueueUpdateDraw(func() {
+		if !n.modal.Open {
+			n.stop()
+			return
+		}
+
+		if status.Type != "" {
+			n.connType = status.Type
+		}
+
+		n.setTable(status)
+	})
+}
+
+// action performs the selected action on the device's network connection.
+func (n *NetworkStatusPanel) action(action string) {
+	info := n.device.Name + " (" + strings.ToUpper(n.connType) + ")"
+
+	switch action {
+	case "Disconnect":
+		InfoMessage("Disconnecting from "+info, true)
+
+		if err := UI.Network.DisconnectDevice(n.device.Address); err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		InfoMessage("Disconnected from "+info, false)
+
+	case "Reconnect":
+		if err := UI.Network.DisconnectDevice(n.device.Address); err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		networkConnect(n.device, n.connType)
+	}
+}
+
+// stop stops watching the network status.
+func (n *NetworkStatusPanel) stop() {
+	n.once.Do(func() {
+		close(n.exit)
+	})
+}
+
+// setTable writes the network status into the panel.
+func (n *NetworkStatusPanel) setTable(status network.ConnectionStatus) {
+	table := n.modal.Table
+	row, _ := table.GetSelection()
+
+	table.Clear()
+
+	state := status.State
+	if state == "" {
+		state = "Disconnected"
+	}
+
+	props := [][]string{
+		{"Device", n.device.Name + " (" + n.device.Address + ")"},
+		{"Type", strings.ToUpper(n.connType)},
+		{"State", state},
+		{"Connection", status.Name},
+		{"Interface", status.Interface},
+		{"IPv4", strings.Join(status.IPv4, ", ")},
+		{"IPv6", strings.Join(status.IPv6, ", ")},
+		{"Gateway", status.Gateway},
+		{"DNS", strings.Join(status.DNS, ", ")},
+	}
+	if status.Interface != "" {
+		props = append(props,
+			[]string{"Received", formatSize(int64(status.RxBytes))},
+			[]string{"Transmitted", formatSize(int64(status.TxBytes))},
+		)
+	}
+
+	setCell := func(row, col int, text string) *tview.TableCell {
+		cell := tview.NewTableCell(text).
+			SetExpansion(1).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Bold(true).
+				Underline(true),
+			)
+
+		table.SetCell(row, col, cell)
+
+		return cell
+	}
+
+	index := 0
+	for _, prop := range props {
+		if prop[1] == "" {
+			continue
+		}
+
+		setCell(index, 0, "[::b]"+prop[0]+":")
+		setCell(index, 1, tview.Escape(prop[1]))
+
+		index++
+	}
+
+	index++
+	for _, action := range []string{"Disconnect", "Reconnect"} {
+		if action == "Disconnect" && status.Type == "" {
+			continue
+		}
+
+		setCell(index, 0, "[::b]["+action+"[]").SetReference(action)
+		index++
+	}
+
+	if row >= table.GetRowCount() {
+		row = table.GetRowCount() - 1
+	}
+	table.Select(row, 0)
+
+	if height := table.GetRowCount() + 4; n.modal.Height != height {
+		n.modal.Height = height
+		n.modal.pageHeight = 0
+
+		ResizeModal()
+	}
+}
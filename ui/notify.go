@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/notify"
+)
+
+// The different categories of desktop notifications.
+const (
+	NotifyBattery    = "battery"
+	NotifyConnection = "connection"
+	NotifyPairing    = "pairing"
+	NotifyPush       = "push"
+	NotifyTransfer   = "transfer"
+)
+
+// SendNotification sends a desktop notification, if notifications
+// of the provided category are enabled. The urgency is normal,
+// unless an urgency is provided.
+func SendNotification(category, summary, body string, urgency ...notify.Urgency) {
+	if !notificationEnabled(category) {
+		return
+	}
+
+	level := notify.UrgencyNormal
+	if urgency != nil {
+		level = urgency[0]
+	}
+
+	notify.Send(summary, body, level)
+}
+
+// ConfirmWithNotification shows a confirmation modal, and if notifications
+// of the provided category are enabled, sends a notification with accept
+// and reject actions. The first reply from either of them is returned.
+func ConfirmWithNotification(category, name, title, message, body string) string {
+	return promptWithNotification(
+		category, title, body,
+		func() string {
+			return NewConfirmModal(name, title, message)
+		},
+		func(reply string) {
+			AnswerConfirmModal(name, reply)
+		},
+		notify.Action{Key: "y", Label: "Accept"},
+		notify.Action{Key: "n", Label: "Reject"},
+	)
+}
+
+// InputWithNotification shows an input prompt, and if notifications of the
+// provided category are enabled, sends a notification with the provided actions.
+// The first reply from either of them is returned.
+func InputWithNotification(category, label, summary, body string, actions ...notify.Action) string {
+	return promptWithNotification(
+		category, summary, body,
+		func() string {
+			return SetInput(label)
+		},
+		func(reply string) {
+			AnswerInput(reply)
+		},
+		actions...,
+	)
+}
+
+// promptWithNotification displays the prompt and a notification with actions
+// simultaneously, and returns the first reply. If the reply is received from
+// the notification, the prompt is answered with the same reply.
+func promptWithNotification(
+	category, summary, body string,
+	prompt func() string, answer func(reply string),
+	actions ...notify.Action,
+) string {
+	if !notificationEnabled(category) {
+		return prompt()
+	}
+
+	id, actionReply, err := notify.SendWithActions(summary, body, notify.UrgencyCritical, actions...)
+	if err != nil {
+		return prompt()
+	}
+
+	promptReply := make(chan string, 1)
+	go func() {
+		promptReply <- prompt()
+	}()
+
+	select {
+	case reply := <-promptReply:
+		notify.Close(id)
+
+		return reply
+
+	case reply := <-actionReply:
+		if reply == "" {
+			return <-promptReply
+		}
+
+		answer(reply)
+		<-promptReply
+
+		return reply
+	}
+}
+
+// notifyConnection sends a notification when the device connects or disconnects.
+func notifyConnection(device bluez.Device, connected bool) {
+	state := "Disconnected"
+	if connected {
+		state = "Connected"
+	}
+
+	SendNotification(NotifyConnection, state, device.Name+" ("+device.Address+")")
+}
+
+// notificationEnabled returns whether notifications of the category are enabled.
+func notificationEnabled(category string) bool {
+	return cmd.IsPropertyEnabled("notifications." + category)
+}
@@ -2,15 +2,22 @@ package ui
 
 import (
 	"sort"
+	"time"
 
 	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
 	"github.com/darkhz/bluetuith/theme"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
 )
 
 var device bluez.Device
 
+// audioCardTimeout is the time to wait for a device's sound card
+// to appear after the device has connected.
+const audioCardTimeout = 10 * time.Second
+
 // audioProfiles shows a popup to select the audio profile.
 func audioProfiles() {
 	device = getDeviceFromSelection(false)
@@ -89,6 +96,10 @@ func setProfile(profileMenu *tview.Table, row, column int) {
 		return
 	}
 
+	if err := cmd.SaveDeviceProperty(device.Address, "audio-profile", profile.Name); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot save audio profile"))
+	}
+
 	markActiveProfile(profileMenu, device, row)
 }
 
@@ -113,3 +124,61 @@ func markActiveProfile(profileMenu *tview.Table, device bluez.Device, index ...i
 		)
 	}
 }
+
+// routeAudio sets the device's sink and source as the default, and moves
+// all existing audio streams to the device.
+func routeAudio(device bluez.Device) bool {
+	InfoMessage("Routing audio to "+device.Name, true)
+
+	if err := bluez.RouteAudio(device.Address); err != nil {
+		ErrorMessage(err)
+		return false
+	}
+
+	InfoMessage("Routed audio to "+device.Name, false)
+
+	return true
+}
+
+// setupDeviceAudio applies the stored audio settings of the device
+// once it has connected.
+func setupDeviceAudio(device bluez.Device) {
+	applyAudioProfile(device)
+	autoRouteAudio(device)
+}
+
+// applyAudioProfile sets the stored audio profile of the device
+// once its sound card appears.
+func applyAudioProfile(device bluez.Device) {
+	profileName := cmd.GetDeviceProperty(device.Address, "audio-profile")
+	if profileName == "" {
+		return
+	}
+
+	if err := bluez.WaitForAudioCard(device.Address, audioCardTimeout); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot apply audio profile for "+device.Name))
+		return
+	}
+
+	if err := bluez.SetDeviceAudioProfile(device.Address, profileName); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot apply audio profile for "+device.Name))
+		return
+	}
+
+	InfoMessage("Applied audio profile "+profileName+" for "+device.Name, false)
+}
+
+// autoRouteAudio routes audio to the device once its sound card appears,
+// if the "audio-route" setting is enabled for the device.
+func autoRouteAudio(device bluez.Device) {
+	if !cmd.IsDevicePropertyEnabled(device.Address, "audio-route") {
+		return
+	}
+
+	if err := bluez.WaitForAudioEndpoints(device.Address, audioCardTimeout); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	routeAudio(device)
+}
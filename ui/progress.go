@@ -132,9 +132,14 @@ func StartProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferPropert
 			switch props.TransferProperties.Status {
 			case "error":
 				ErrorMessage(errors.New("Transfer has failed for " + props.TransferProperties.Name))
+				SendNotification(NotifyTransfer, "Transfer failed", props.TransferProperties.Name)
 				fallthrough
 
 			case "complete":
+				if props.TransferProperties.Status == "complete" {
+					SendNotification(NotifyTransfer, "Transfer complete", props.TransferProperties.Name)
+				}
+
 				progress.status = props.TransferProperties.Status
 				progress.FinishProgress(transferPath, path...)
 				return true
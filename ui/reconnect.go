@@ -0,0 +1,389 @@
+package ui
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+)
+
+// ReconnectPolicy describes when a device should be reconnected.
+type ReconnectPolicy struct {
+	Name, Title string
+}
+
+// Reconnector stores the reconnection state of each device,
+// mapped to the device's path.
+type Reconnector struct {
+	states map[string]*reconnectState
+
+	lock sync.Mutex
+}
+
+// reconnectState stores the reconnection attempts of a device.
+type reconnectState struct {
+	attempts int
+	next     time.Time
+	timer    *time.Timer
+	active   bool
+	failed   bool
+	manual   bool
+}
+
+// The different reconnect triggers.
+const (
+	reconnectPowerOn     = "power-on"
+	reconnectRediscovery = "rediscovery"
+	reconnectKeepAlive   = "keep-alive"
+)
+
+const (
+	// reconnectMaxDelay is the maximum delay between reconnection attempts.
+	reconnectMaxDelay = time.Minute
+
+	// reconnectDefaultAttempts is the number of reconnection attempts,
+	// if no number of attempts is configured.
+	reconnectDefaultAttempts = 5
+)
+
+var (
+	reconnector Reconnector
+
+	reconnectPolicies = []ReconnectPolicy{
+		{"", "Never"},
+		{reconnectPowerOn, "On adapter power-on"},
+		{reconnectRediscovery, "On rediscovery"},
+		{reconnectKeepAlive, "Keep-alive"},
+	}
+)
+
+// reconnectMenu shows a popup to select the auto-reconnect
+// policy of the selected device.
+func reconnectMenu() {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	setContextMenu(
+		"device",
+		func(policyMenu *tview.Table) {
+			row, _ := policyMenu.GetSelection()
+
+			setReconnectPolicy(policyMenu, row, 0)
+		}, nil,
+		func(policyMenu *tview.Table) (int, int) {
+			var width, index int
+
+			policyMenu.SetSelectorWrap(true)
+
+			for row, policy := range reconnectPolicies {
+				if policy.Name == reconnectPolicy(device.Address) {
+					index = row
+				}
+
+				if len(policy.Title) > width {
+					width = len(policy.Title)
+				}
+
+				policyMenu.SetCell(row, 1, tview.NewTableCell(policy.Title).
+					SetExpansion(1).
+					SetReference(policy).
+					SetAlign(tview.AlignLeft).
+					SetOnClickedFunc(setReconnectPolicy).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(tcell.Style{}.
+						Foreground(theme.GetColor(theme.ThemeText)).
+						Background(theme.BackgroundColor(theme.ThemeText)),
+					),
+				)
+			}
+
+			markReconnectPolicy(policyMenu, device)
+
+			return width - 16, index
+		},
+	)
+}
+
+// setReconnectPolicy sets the selected auto-reconnect policy.
+func setReconnectPolicy(policyMenu *tview.Table, row, column int) {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	cell := policyMenu.GetCell(row, 1)
+	if cell == nil {
+		return
+	}
+
+	policy, ok := cell.GetReference().(ReconnectPolicy)
+	if !ok {
+		return
+	}
+
+	if err := cmd.SaveDeviceProperty(device.Address, "reconnect", policy.Name); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot save reconnect policy"))
+	}
+
+	resetReconnect(device)
+	markReconnectPolicy(policyMenu, device)
+}
+
+// markReconnectPolicy marks the auto-reconnect policy of the device.
+func markReconnectPolicy(policyMenu *tview.Table, device bluez.Device) {
+	for row := 0; row < policyMenu.GetRowCount(); row++ {
+		cell := policyMenu.GetCell(row, 1)
+		if cell == nil {
+			continue
+		}
+
+		policy, ok := cell.GetReference().(ReconnectPolicy)
+		if !ok {
+			continue
+		}
+
+		setMenuMarker(policyMenu, row, policy.Name == reconnectPolicy(device.Address))
+	}
+}
+
+// reconnectAdapterEvent reconnects the devices of the adapter
+// once the adapter is powered on.
+func reconnectAdapterEvent(adapter bluez.Adapter) {
+	for _, device := range UI.Bluez.GetAdapterDevices(adapter.Path) {
+		switch reconnectPolicy(device.Address) {
+		case reconnectPowerOn, reconnectKeepAlive:
+			resetReconnect(device)
+			scheduleReconnect(device, reconnectPowerOn)
+		}
+	}
+}
+
+// reconnectDeviceEvent handles connection and discovery changes of the device,
+// and reconnects it according to its auto-reconnect policy.
+func reconnectDeviceEvent(device bluez.Device, connected, connectionChanged, discovered bool) {
+	if reconnectPolicy(device.Address) == "" {
+		return
+	}
+
+	switch {
+	case connectionChanged && connected:
+		resetReconnect(device)
+
+	case connectionChanged && !connected:
+		if isManualDisconnect(device) {
+			return
+		}
+
+		scheduleReconnect(device, reconnectKeepAlive)
+
+	case discovered && !device.Connected:
+		scheduleReconnect(device, reconnectRediscovery)
+	}
+}
+
+// scheduleReconnect schedules a reconnection attempt for the device,
+// if its policy allows it to be reconnected on the provided trigger.
+func scheduleReconnect(device bluez.Device, trigger string) {
+	policy := reconnectPolicy(device.Address)
+	if policy == "" || !device.Paired || device.Blocked {
+		return
+	}
+
+	if trigger != policy && policy != reconnectKeepAlive {
+		return
+	}
+
+	reconnector.lock.Lock()
+	defer reconnector.lock.Unlock()
+
+	state := getReconnectState(device)
+	if state.active || state.timer != nil || state.failed {
+		return
+	}
+
+	delay := time.Until(state.next)
+	if delay < 0 {
+		delay = 0
+	}
+
+	state.timer = time.AfterFunc(delay, func() {
+		attemptReconnect(device, trigger)
+	})
+}
+
+// attemptReconnect attempts to reconnect the device. If the attempt fails,
+// another attempt is scheduled with a backoff, until the configured number
+// of attempts are exhausted.
+func attemptReconnect(device bluez.Device, trigger string) {
+	maxAttempts := reconnectAttempts(device.Address)
+
+	reconnector.lock.Lock()
+	state := getReconnectState(device)
+	state.timer = nil
+
+	if device = UI.Bluez.GetDevice(device.Path); device.Path == "" || device.Connected {
+		reconnector.lock.Unlock()
+		return
+	}
+
+	state.attempts++
+	state.active = true
+	reconnector.lock.Unlock()
+
+	updateReconnectRow(device)
+
+	err := UI.Bluez.Connect(device.Path)
+
+	reconnector.lock.Lock()
+	state.active = false
+	if err == nil {
+		delete(reconnector.states, device.Path)
+		reconnector.lock.Unlock()
+
+		InfoMessage("Reconnected to "+device.Name, false)
+		updateReconnectRow(device)
+
+		return
+	}
+
+	delay := reconnectMaxDelay
+	if state.attempts <= 6 {
+		delay = time.Duration(1<<(state.attempts-1)) * time.Second
+	}
+
+	state.next = time.Now().Add(delay)
+	state.failed = state.attempts >= maxAttempts
+	failed := state.failed
+	reconnector.lock.Unlock()
+
+	updateReconnectRow(device)
+
+	if failed {
+		ErrorMessage(errors.Wrap(err, "Cannot reconnect to "+device.Name))
+		return
+	}
+
+	if trigger != reconnectRediscovery {
+		scheduleReconnect(device, trigger)
+	}
+}
+
+// resetReconnect stops any scheduled reconnection attempts for the device,
+// and resets its reconnection state.
+func resetReconnect(device bluez.Device) {
+	reconnector.lock.Lock()
+	defer reconnector.lock.Unlock()
+
+	state, ok := reconnector.states[device.Path]
+	if !ok {
+		return
+	}
+
+	if state.timer != nil {
+		state.timer.Stop()
+	}
+
+	delete(reconnector.states, device.Path)
+
+	go updateReconnectRow(device)
+}
+
+// setManualDisconnect marks the device as disconnected by the user,
+// so that it is not reconnected by the keep-alive policy.
+func setManualDisconnect(device bluez.Device) {
+	if reconnectPolicy(device.Address) == "" {
+		return
+	}
+
+	reconnector.lock.Lock()
+	defer reconnector.lock.Unlock()
+
+	getReconnectState(device).manual = true
+}
+
+// isManualDisconnect returns whether the device was disconnected by the user.
+func isManualDisconnect(device bluez.Device) bool {
+	reconnector.lock.Lock()
+	defer reconnector.lock.Unlock()
+
+	state, ok := reconnector.states[device.Path]
+	if !ok || !state.manual {
+		return false
+	}
+
+	delete(reconnector.states, device.Path)
+
+	return true
+}
+
+// reconnectStatus returns the reconnection state of the device.
+func reconnectStatus(device bluez.Device) string {
+	reconnector.lock.Lock()
+	defer reconnector.lock.Unlock()
+
+	state, ok := reconnector.states[device.Path]
+	if !ok || state.attempts == 0 {
+		return ""
+	}
+
+	if state.failed {
+		return "Reconnect failed"
+	}
+
+	return "Reconnecting " + strconv.Itoa(state.attempts) + "/" +
+		strconv.Itoa(reconnectAttempts(device.Address))
+}
+
+// updateReconnectRow updates the device's row in the device table.
+func updateReconnectRow(device bluez.Device) {
+	UI.QueueUpdateDraw(func() {
+		if row, ok := checkDeviceTable(device.Path); ok {
+			setDeviceTableInfo(row, UI.Bluez.GetDevice(device.Path))
+		}
+	})
+}
+
+// getReconnectState returns the reconnection state of the device.
+// The reconnector's lock must be held when calling this function.
+func getReconnectState(device bluez.Device) *reconnectState {
+	if reconnector.states == nil {
+		reconnector.states = make(map[string]*reconnectState)
+	}
+
+	state, ok := reconnector.states[device.Path]
+	if !ok {
+		state = &reconnectState{}
+		reconnector.states[device.Path] = state
+	}
+
+	return state
+}
+
+// reconnectPolicy returns the auto-reconnect policy of the device.
+func reconnectPolicy(address string) string {
+	return cmd.GetDeviceProperty(address, "reconnect")
+}
+
+// reconnectAttempts returns the number of reconnection attempts for the device.
+// If no number of attempts is set for the device, the global setting is used.
+func reconnectAttempts(address string) int {
+	value := cmd.GetDeviceProperty(address, "reconnect-attempts")
+	if value == "" {
+		value = cmd.GetProperty("reconnect-attempts")
+	}
+
+	attempts, err := strconv.Atoi(value)
+	if err != nil || attempts <= 0 {
+		return reconnectDefaultAttempts
+	}
+
+	return attempts
+}
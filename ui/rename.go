@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"github.com/darkhz/tview"
+	"github.com/pkg/errors"
+)
+
+// renameDevice shows a dialog to set the alias of the selected device.
+func renameDevice() {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	renameModal("Rename Device", device.Alias, device.Name, func(alias string) error {
+		return UI.Bluez.SetDeviceProperty(device.Path, "Alias", alias)
+	})
+}
+
+// renameAdapter shows a dialog to set the alias of the current adapter.
+func renameAdapter() {
+	adapter := UI.Bluez.GetCurrentAdapter()
+	if adapter.Path == "" {
+		return
+	}
+
+	renameModal("Rename Adapter", adapter.Alias, adapter.Name, func(alias string) error {
+		return UI.Bluez.SetAdapterProperty(adapter.Path, "Alias", alias)
+	})
+}
+
+// renameModal shows a dialog to edit an alias. Setting an empty alias
+// resets it to the hardware name.
+func renameModal(title, alias, name string, setAlias func(alias string) error) {
+	form := tview.NewForm()
+	form.AddInputField("Alias", alias, 40, nil, nil)
+
+	modal := NewFormModal("rename", title, form, 9, 60)
+
+	rename := func(alias string) {
+		modal.Exit(false)
+
+		go func() {
+			if err := setAlias(alias); err != nil {
+				ErrorMessage(errors.Wrap(err, "Cannot set alias"))
+				return
+			}
+
+			if alias == "" {
+				InfoMessage("Alias reset to "+name, false)
+				return
+			}
+
+			InfoMessage("Renamed "+name+" to "+alias, false)
+		}()
+	}
+
+	form.AddButton("Rename", func() {
+		rename(form.GetFormItem(0).(*tview.InputField).GetText())
+	})
+	form.AddButton("Reset", func() {
+		rename("")
+	})
+	form.AddButton("Cancel", func() {
+		modal.Exit(false)
+	})
+
+	modal.Show()
+}
@@ -0,0 +1,410 @@
+package ui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+)
+
+// SerialTerminal describes the RFCOMM serial terminal.
+type SerialTerminal struct {
+	device bluez.Device
+	conn   *os.File
+	log    *os.File
+	open   bool
+
+	echo       bool
+	hexView    bool
+	lineEnding int
+
+	title  *tview.TextView
+	output *tview.TextView
+	input  *tview.InputField
+	flex   *tview.Flex
+
+	lock sync.Mutex
+}
+
+// serialMaxLines is the maximum number of lines kept in the terminal output.
+const serialMaxLines = 5000
+
+var (
+	serialTerminal SerialTerminal
+
+	serialLineEndings = [][]string{
+		{"CRLF", "\r\n"},
+		{"LF", "\n"},
+		{"CR", "\r"},
+		{"None", ""},
+	}
+)
+
+// showSerialTerminal shows the serial terminal, and connects
+// to the serial port of the selected device.
+func showSerialTerminal() {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	err := UI.Bluez.RegisterSerialProfile(bluez.SerialHandler{
+		OnConnect:    serialConnected,
+		OnDisconnect: serialDisconnected,
+	})
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot register serial port profile"))
+		return
+	}
+
+	serialTerminal.lock.Lock()
+	serialTerminal.device = device
+	serialTerminal.open = true
+	serialTerminal.lock.Unlock()
+
+	UI.QueueUpdateDraw(func() {
+		serialTerminalView()
+
+		serialTerminal.output.Clear()
+		setSerialTerminalTitle()
+
+		UI.Pages.AddAndSwitchToPage("serialterminal", serialTerminal.flex, true)
+	})
+
+	connectSerial(device)
+}
+
+// serialTerminalView initializes the serial terminal.
+func serialTerminalView() {
+	if serialTerminal.flex != nil {
+		return
+	}
+
+	serialTerminal.title = tview.NewTextView()
+	serialTerminal.title.SetDynamicColors(true)
+	serialTerminal.title.SetTextAlign(tview.AlignLeft)
+	serialTerminal.title.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	serialTerminal.output = tview.NewTextView()
+	serialTerminal.output.SetMaxLines(serialMaxLines)
+	serialTerminal.output.SetDynamicColors(true)
+	serialTerminal.output.SetScrollable(true)
+	serialTerminal.output.SetTextAlign(tview.AlignLeft)
+	serialTerminal.output.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	serialTerminal.output.SetTextColor(theme.GetColor(theme.ThemeText))
+	serialTerminal.output.SetChangedFunc(func() {
+		serialTerminal.output.ScrollToEnd()
+	})
+
+	serialTerminal.input = tview.NewInputField()
+	serialTerminal.input.SetLabel("[::b]> ")
+	serialTerminal.input.SetLabelColor(theme.GetColor(theme.ThemeText))
+	serialTerminal.input.SetFieldTextColor(theme.GetColor(theme.ThemeText))
+	serialTerminal.input.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	serialTerminal.input.SetFieldBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	serialTerminal.input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+
+		text := serialTerminal.input.GetText()
+		serialTerminal.input.SetText("")
+
+		go sendSerial(text)
+	})
+	serialTerminal.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch cmd.KeyOperation(event, cmd.KeyContextSerial) {
+		case cmd.KeyClose:
+			go closeSerialTerminal()
+
+		case cmd.KeySerialToggleEcho:
+			serialTerminal.lock.Lock()
+			serialTerminal.echo = !serialTerminal.echo
+			serialTerminal.lock.Unlock()
+
+			setSerialTerminalTitle()
+
+		case cmd.KeySerialLineEnding:
+			serialTerminal.lock.Lock()
+			serialTerminal.lineEnding = (serialTerminal.lineEnding + 1) % len(serialLineEndings)
+			serialTerminal.lock.Unlock()
+
+			setSerialTerminalTitle()
+
+		case cmd.KeySerialHexView:
+			serialTerminal.lock.Lock()
+			serialTerminal.hexView = !serialTerminal.hexView
+			serialTerminal.lock.Unlock()
+
+			setSerialTerminalTitle()
+
+		case cmd.KeySerialToggleLog:
+			go toggleSerialLog()
+
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	serialTerminal.flex = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(serialTerminal.title, 1, 0, false).
+		AddItem(serialTerminal.output, 0, 10, false).
+		AddItem(horizontalLine(), 1, 0, false).
+		AddItem(serialTerminal.input, 1, 0, true)
+}
+
+// connectSerial connects to the serial port of the device.
+func connectSerial(device bluez.Device) {
+	InfoMessage("Connecting to serial port of "+device.Name, true)
+
+	if err := UI.Bluez.ConnectProfile(device.Path, bluez.SerialPortUUID()); err != nil && !bluez.IsAlreadyConnected(err) {
+		ErrorMessage(errors.Wrap(err, "Cannot connect to serial port"))
+		return
+	}
+}
+
+// serialConnected is called when a RFCOMM connection to the serial port is established.
+// Connections initiated by other devices are accepted if the terminal is not connected.
+func serialConnected(connection bluez.SerialConnection) error {
+	serialTerminal.lock.Lock()
+	if !serialTerminal.open {
+		serialTerminal.lock.Unlock()
+		return errors.New("Serial terminal is not open")
+	}
+	if serialTerminal.conn != nil {
+		serialTerminal.lock.Unlock()
+		return errors.New("Serial terminal is already connected")
+	}
+
+	if connection.DevicePath != serialTerminal.device.Path {
+		serialTerminal.device = UI.Bluez.GetDevice(connection.DevicePath)
+	}
+	serialTerminal.conn = connection.File
+	name := serialTerminal.device.Name
+	serialTerminal.lock.Unlock()
+
+	go readSerial(connection.File)
+
+	InfoMessage("Connected to serial port of "+name, false)
+	UI.QueueUpdateDraw(func() {
+		setSerialTerminalTitle()
+	})
+
+	return nil
+}
+
+// serialDisconnected is called when the device is disconnected from the serial port.
+func serialDisconnected(devicePath string) {
+	serialTerminal.lock.Lock()
+	defer serialTerminal.lock.Unlock()
+
+	if serialTerminal.conn != nil && serialTerminal.device.Path == devicePath {
+		serialTerminal.conn.Close()
+	}
+}
+
+// readSerial reads the data received from the serial port, and writes
+// it to the terminal output and the log file.
+func readSerial(conn *os.File) {
+	buf := make([]byte, 1024)
+
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+
+			writeSerialOutput(data, false)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	conn.Close()
+
+	serialTerminal.lock.Lock()
+	if serialTerminal.conn != conn {
+		serialTerminal.lock.Unlock()
+		return
+	}
+	serialTerminal.conn = nil
+	name := serialTerminal.device.Name
+	serialTerminal.lock.Unlock()
+
+	InfoMessage("Disconnected from serial port of "+name, false)
+	UI.QueueUpdateDraw(func() {
+		setSerialTerminalTitle()
+	})
+}
+
+// sendSerial sends the text to the serial port, followed by the selected line ending.
+// If the text is a 0x-prefixed hex value, the decoded bytes are sent as is.
+func sendSerial(text string) {
+	var data []byte
+
+	serialTerminal.lock.Lock()
+	conn := serialTerminal.conn
+	echo := serialTerminal.echo
+	lineEnding := serialLineEndings[serialTerminal.lineEnding][1]
+	serialTerminal.lock.Unlock()
+
+	if conn == nil {
+		ErrorMessage(errors.New("Serial port is not connected"))
+		return
+	}
+
+	if strings.HasPrefix(text, "0x") {
+		value, err := hex.DecodeString(strings.ReplaceAll(strings.TrimPrefix(text, "0x"), " ", ""))
+		if err != nil {
+			ErrorMessage(errors.New("Invalid hex value"))
+			return
+		}
+
+		data = value
+	} else {
+		data = []byte(text + lineEnding)
+	}
+
+	if _, err := conn.Write(data); err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot write to serial port"))
+		return
+	}
+
+	if echo {
+		writeSerialOutput(data, true)
+	}
+}
+
+// writeSerialOutput writes the data to the terminal output and the log file.
+func writeSerialOutput(data []byte, sent bool) {
+	serialTerminal.lock.Lock()
+	hexView := serialTerminal.hexView
+	if serialTerminal.log != nil {
+		serialTerminal.log.Write(data)
+	}
+	serialTerminal.lock.Unlock()
+
+	var text string
+	if hexView {
+		text = fmt.Sprintf("% x\n", data)
+	} else {
+		text = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(string(data))
+	}
+
+	text = tview.Escape(text)
+	if sent {
+		text = theme.ColorWrap(theme.ThemeText, text, "::b")
+	}
+
+	UI.QueueUpdateDraw(func() {
+		fmt.Fprint(serialTerminal.output, text)
+	})
+}
+
+// toggleSerialLog starts or stops logging the serial data to a file.
+func toggleSerialLog() {
+	serialTerminal.lock.Lock()
+	logFile := serialTerminal.log
+	serialTerminal.log = nil
+	serialTerminal.lock.Unlock()
+
+	if logFile != nil {
+		logFile.Close()
+		InfoMessage("Stopped logging to "+logFile.Name(), false)
+
+		UI.QueueUpdateDraw(func() {
+			setSerialTerminalTitle()
+		})
+
+		return
+	}
+
+	path := SetInput("Log file:", struct{}{})
+	if path == "" {
+		return
+	}
+
+	logFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		ErrorMessage(errors.Wrap(err, "Cannot open log file"))
+		return
+	}
+
+	serialTerminal.lock.Lock()
+	serialTerminal.log = logFile
+	serialTerminal.lock.Unlock()
+
+	InfoMessage("Logging to "+path, false)
+	UI.QueueUpdateDraw(func() {
+		setSerialTerminalTitle()
+	})
+}
+
+// closeSerialTerminal disconnects from the serial port, and closes the serial terminal.
+func closeSerialTerminal() {
+	serialTerminal.lock.Lock()
+	device := serialTerminal.device
+	conn := serialTerminal.conn
+	logFile := serialTerminal.log
+	serialTerminal.conn = nil
+	serialTerminal.log = nil
+	serialTerminal.open = false
+	serialTerminal.lock.Unlock()
+
+	if conn != nil {
+		conn.Close()
+		UI.Bluez.DisconnectProfile(device.Path, bluez.SerialPortUUID())
+	}
+	if logFile != nil {
+		logFile.Close()
+	}
+
+	UI.Bluez.UnregisterSerialProfile()
+
+	UI.QueueUpdateDraw(func() {
+		UI.Pages.RemovePage("serialterminal")
+		UI.Pages.SwitchToPage("main")
+	})
+}
+
+// setSerialTerminalTitle sets the title of the serial terminal.
+func setSerialTerminalTitle() {
+	serialTerminal.lock.Lock()
+	defer serialTerminal.lock.Unlock()
+
+	state := "Disconnected"
+	if serialTerminal.conn != nil {
+		state = "Connected"
+	}
+
+	options := []string{
+		state,
+		"Line Ending: " + serialLineEndings[serialTerminal.lineEnding][0],
+	}
+	if serialTerminal.echo {
+		options = append(options, "Echo")
+	}
+	if serialTerminal.hexView {
+		options = append(options, "Hex")
+	}
+	if serialTerminal.log != nil {
+		options = append(options, "Logging")
+	}
+
+	serialTerminal.title.SetText(
+		theme.ColorWrap(theme.ThemeText, "Serial Terminal: "+serialTerminal.device.Name, "::bu") +
+			theme.ColorWrap(theme.ThemeText, " ("+strings.Join(options, ", ")+")"),
+	)
+}
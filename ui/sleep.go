@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+)
+
+// SleepState stores the adapter states and the connected devices,
+// which were recorded before the system went to sleep.
+type SleepState struct {
+	adapters []bluez.Adapter
+	devices  []bluez.Device
+
+	inhibitor *os.File
+
+	lock sync.Mutex
+}
+
+const (
+	dbusLogindName         = "org.freedesktop.login1"
+	dbusLogindPath         = "/org/freedesktop/login1"
+	dbusLogindManagerIface = "org.freedesktop.login1.Manager"
+)
+
+const (
+	// resumeAdapterTimeout is the time to wait for the adapters
+	// to reappear after the system has resumed.
+	resumeAdapterTimeout = 10 * time.Second
+
+	// resumeConnectAttempts is the number of attempts to reconnect
+	// each device after the system has resumed.
+	resumeConnectAttempts = 3
+)
+
+var sleepState SleepState
+
+// watchSleepEvent listens to logind's PrepareForSleep signal, and records
+// the adapter states and connected devices before the system sleeps,
+// and restores them after the system resumes.
+func watchSleepEvent() {
+	conn := UI.Bluez.Conn()
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(dbusLogindPath),
+		dbus.WithMatchInterface(dbusLogindManagerIface),
+		dbus.WithMatchMember("PrepareForSleep"),
+	); err != nil {
+		return
+	}
+
+	sleepSignal := make(chan *dbus.Signal, 1)
+	conn.Signal(sleepSignal)
+	defer conn.RemoveSignal(sleepSignal)
+
+	takeSleepInhibitor()
+
+	for signal := range sleepSignal {
+		if signal.Name != dbusLogindManagerIface+".PrepareForSleep" || len(signal.Body) == 0 {
+			continue
+		}
+
+		start, ok := signal.Body[0].(bool)
+		if !ok {
+			continue
+		}
+
+		if start {
+			prepareForSleep()
+			continue
+		}
+
+		go resumeFromSleep()
+	}
+}
+
+// prepareForSleep records the adapter states and the connected devices,
+// and releases the sleep inhibitor so that the system can sleep.
+func prepareForSleep() {
+	sleepState.lock.Lock()
+	defer sleepState.lock.Unlock()
+
+	sleepState.adapters = UI.Bluez.GetAdapters()
+	sleepState.devices = nil
+
+	for _, adapter := range sleepState.adapters {
+		for _, device := range UI.Bluez.GetAdapterDevices(adapter.Path) {
+			if device.Connected {
+				sleepState.devices = append(sleepState.devices, device)
+			}
+		}
+	}
+
+	if sleepState.inhibitor != nil {
+		sleepState.inhibitor.Close()
+		sleepState.inhibitor = nil
+	}
+}
+
+// resumeFromSleep restores the recorded adapter states, applies the
+// adapter states from the "adapter-states" option, and reconnects
+// the recorded devices in order.
+func resumeFromSleep() {
+	sleepState.lock.Lock()
+	adapters, devices := sleepState.adapters, sleepState.devices
+	sleepState.adapters, sleepState.devices = nil, nil
+	sleepState.lock.Unlock()
+
+	defer takeSleepInhibitor()
+
+	for _, adapter := range adapters {
+		if !waitForAdapter(adapter.Path) {
+			continue
+		}
+
+		if err := restoreAdapterState(adapter); err != nil {
+			ErrorMessage(err)
+		}
+	}
+
+	setAdapterStates()
+
+	for _, device := range devices {
+		if UI.Bluez.GetDevice(device.Path).Connected {
+			continue
+		}
+
+		// Devices with these policies are reconnected once their adapter is restored.
+		switch reconnectPolicy(device.Address) {
+		case reconnectPowerOn, reconnectKeepAlive:
+			continue
+		}
+
+		InfoMessage("Reconnecting to "+device.Name, true)
+
+		var err error
+		for attempt := 0; attempt < resumeConnectAttempts; attempt++ {
+			if err = UI.Bluez.Connect(device.Path); err == nil {
+				break
+			}
+
+			time.Sleep(2 * time.Second)
+		}
+		if err != nil {
+			ErrorMessage(errors.Wrap(err, "Cannot reconnect to "+device.Name))
+			continue
+		}
+
+		InfoMessage("Reconnected to "+device.Name, false)
+	}
+}
+
+// restoreAdapterState restores the recorded states of the adapter.
+func restoreAdapterState(adapter bluez.Adapter) error {
+	if !adapter.Powered {
+		return nil
+	}
+
+	if err := UI.Bluez.Power(adapter.Path, true); err != nil {
+		return errors.Wrap(err, "Cannot power on "+bluez.GetAdapterID(adapter.Path))
+	}
+
+	for property, enabled := range map[string]bool{
+		"Discoverable": adapter.Discoverable,
+		"Pairable":     adapter.Pairable,
+	} {
+		if err := UI.Bluez.SetAdapterProperty(adapter.Path, property, enabled); err != nil {
+			return errors.Wrap(err, "Cannot restore "+property+" state of "+bluez.GetAdapterID(adapter.Path))
+		}
+	}
+
+	if adapter.Discovering {
+		if err := applyDiscoveryFilter(adapter); err != nil {
+			ErrorMessage(err)
+		}
+
+		return UI.Bluez.StartDiscovery(adapter.Path)
+	}
+
+	return nil
+}
+
+// waitForAdapter waits for the adapter to reappear after the system has resumed.
+func waitForAdapter(adapterPath string) bool {
+	for start := time.Now(); time.Since(start) < resumeAdapterTimeout; time.Sleep(time.Second) {
+		for _, adapter := range UI.Bluez.GetAdapters() {
+			if adapter.Path == adapterPath {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// takeSleepInhibitor takes a delay inhibitor lock from logind, so that
+// the adapter states and connected devices can be recorded before sleep.
+func takeSleepInhibitor() {
+	var fd dbus.UnixFD
+
+	err := UI.Bluez.Conn().Object(dbusLogindName, dbusLogindPath).Call(
+		dbusLogindManagerIface+".Inhibit", 0,
+		"sleep", "bluetuith", "Record Bluetooth devices and adapter states", "delay",
+	).Store(&fd)
+	if err != nil {
+		return
+	}
+
+	sleepState.lock.Lock()
+	defer sleepState.lock.Unlock()
+
+	if sleepState.inhibitor != nil {
+		sleepState.inhibitor.Close()
+	}
+
+	sleepState.inhibitor = os.NewFile(uintptr(fd), "inhibitor")
+}
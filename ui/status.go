@@ -24,6 +24,7 @@ type Status struct {
 	sctx    context.Context
 	scancel context.CancelFunc
 	msgchan chan message
+	answer  func(reply string)
 
 	itemCount int
 
@@ -83,6 +84,7 @@ func SetInput(label string, multichar ...struct{}) string {
 
 	go func(ch chan bool) {
 		exit := func() {
+			UI.Status.answer = nil
 			UI.Status.SwitchToPage("messages")
 
 			_, item := UI.Pages.GetFrontPage()
@@ -129,6 +131,13 @@ func SetInput(label string, multichar ...struct{}) string {
 				})
 			}
 
+			UI.Status.answer = func(reply string) {
+				UI.Status.InputField.SetText(reply)
+				ch <- reply != ""
+
+				exit()
+			}
+
 			UI.Status.SwitchToPage("input")
 			UI.SetFocus(UI.Status.InputField)
 		})
@@ -142,6 +151,15 @@ func SetInput(label string, multichar ...struct{}) string {
 	return UI.Status.InputField.GetText()
 }
 
+// AnswerInput answers the currently displayed input prompt with the reply.
+func AnswerInput(reply string) {
+	UI.QueueUpdateDraw(func() {
+		if UI.Status.answer != nil {
+			UI.Status.answer(reply)
+		}
+	})
+}
+
 // InfoMessage sends an info message to the status bar.
 func InfoMessage(text string, persist bool) {
 	if UI.Status.msgchan == nil {
@@ -156,6 +174,20 @@ func InfoMessage(text string, persist bool) {
 	}
 }
 
+// WarningMessage sends a warning message to the status bar.
+func WarningMessage(text string) {
+	if UI.Status.msgchan == nil {
+		return
+	}
+
+	select {
+	case UI.Status.msgchan <- message{theme.ColorWrap(theme.ThemeStatusError, "Warning: "+text), false}:
+		return
+
+	default:
+	}
+}
+
 // ErrorMessage sends an error message to the status bar.
 func ErrorMessage(err error) {
 	if UI.Status.msgchan == nil {
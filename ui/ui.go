@@ -30,7 +30,7 @@ type Application struct {
 	Obex *bluez.Obex
 
 	// network holds the current network connection.
-	Network *network.Network
+	Network network.Backend
 
 	suspend     bool
 	warn, page  string
@@ -72,13 +72,15 @@ func StartUI() {
 		page, _ := UI.Pages.GetFrontPage()
 
 		contexts := map[string]cmd.KeyContext{
-			"main":         cmd.KeyContextDevice,
-			"filepicker":   cmd.KeyContextFiles,
-			"progressview": cmd.KeyContextProgress,
+			"main":           cmd.KeyContextDevice,
+			"filepicker":     cmd.KeyContextFiles,
+			"progressview":   cmd.KeyContextProgress,
+			"gattexplorer":   cmd.KeyContextGatt,
+			"serialterminal": cmd.KeyContextSerial,
 		}
 
 		switch page {
-		case "main", "filepicker", "progressview":
+		case "main", "filepicker", "progressview", "gattexplorer", "serialterminal":
 			UI.page = page
 			UI.pageContext = contexts[page]
 
@@ -121,6 +123,7 @@ func StartUI() {
 	})
 	UI.SetBeforeDrawFunc(func(t tcell.Screen) bool {
 		ResizeModal()
+		ResizeDeviceTable()
 		suspendUI(t)
 
 		return false
@@ -149,12 +152,14 @@ func StopUI() {
 	UI.Stop()
 }
 
-// SetConnections sets the connections to bluez and networkmanager.
-func SetConnections(b *bluez.Bluez, o *bluez.Obex, n *network.Network, warn string) {
+// SetConnections sets the connections to bluez and the network backend.
+func SetConnections(b *bluez.Bluez, o *bluez.Obex, n network.Backend, warn string) {
 	UI.Bluez = b
 	UI.Obex = o
 	UI.Network = n
 	UI.warn = warn
+
+	UI.Bluez.SetAdvertisingReleaseHandler(advertisingReleased)
 }
 
 // suspendUI suspends the application.
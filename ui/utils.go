@@ -16,13 +16,6 @@ import (
 	"github.com/gdamore/tcell/v2"
 )
 
-// SetTrusted sets the trusted state of a device.
-func SetTrusted(devicePath string, enable bool) error {
-	UI.Bluez.SetDeviceProperty(devicePath, "Trusted", true)
-
-	return nil
-}
-
 // GetDeviceFromPath gets a device from the device path.
 func GetDeviceFromPath(devicePath string) (bluez.Device, error) {
 	device := UI.Bluez.GetDevice(devicePath)
@@ -50,24 +43,10 @@ func formatSize(size int64) string {
 }
 
 // savefile moves a file from the obex cache to a specified user-accessible directory.
-// If the directory is not specified, it automatically creates a directory in the
-// user's home path and moves the file there.
 func savefile(path string) error {
-	userpath := cmd.GetProperty("receive-dir")
-	if userpath == "" {
-		homedir, err := os.UserHomeDir()
-		if err != nil {
-			return err
-		}
-
-		userpath = filepath.Join(homedir, "bluetuith")
-
-		if _, err := os.Stat(userpath); err != nil {
-			err = os.Mkdir(userpath, 0700)
-			if err != nil {
-				return err
-			}
-		}
+	userpath, err := cmd.ReceiveDir()
+	if err != nil {
+		return err
 	}
 
 	return os.Rename(path, filepath.Join(userpath, filepath.Base(path)))
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+)
+
+// AudioVolumes stores the sink of each device's sound card,
+// mapped to the device's address.
+type AudioVolumes struct {
+	sinks map[string]bluez.AudioEndpoint
+	lock  sync.Mutex
+}
+
+var volumes AudioVolumes
+
+// refreshVolumes updates the stored sinks, and updates
+// the audio information in the device table.
+func refreshVolumes() {
+	sinks, err := bluez.ListDeviceSinks()
+	if err != nil {
+		return
+	}
+
+	volumes.lock.Lock()
+	volumes.sinks = sinks
+	volumes.lock.Unlock()
+
+	UI.QueueUpdateDraw(func() {
+		for row := 0; row < DeviceTable.GetRowCount(); row++ {
+			cell := DeviceTable.GetCell(row, 0)
+			if cell == nil {
+				continue
+			}
+
+			device, ok := cell.GetReference().(bluez.Device)
+			if !ok {
+				continue
+			}
+
+			setDeviceTableInfo(row, device)
+		}
+	})
+}
+
+// audioEvent handles pulseaudio events.
+func audioEvent(event bluez.AudioEvent) {
+	switch event.Type {
+	case bluez.AudioEventSink:
+		refreshVolumes()
+
+	case bluez.AudioEventCard:
+		refreshVolumes()
+
+		for _, device := range UI.Bluez.GetDevices() {
+			if device.Connected {
+				go refreshConnectedProfiles(device)
+			}
+		}
+
+	case bluez.AudioEventSourceOutput:
+		go profileSwitchEvent(event)
+	}
+}
+
+// getDeviceSink returns the sink of the device's sound card.
+func getDeviceSink(address string) (bluez.AudioEndpoint, bool) {
+	volumes.lock.Lock()
+	defer volumes.lock.Unlock()
+
+	sink, ok := volumes.sinks[address]
+
+	return sink, ok
+}
+
+// deviceVolumeText returns the volume information of the device.
+func deviceVolumeText(device bluez.Device) string {
+	if !device.Connected {
+		return ""
+	}
+
+	sink, ok := getDeviceSink(device.Address)
+	if !ok {
+		return ""
+	}
+
+	if sink.Mute {
+		return "[Muted[]"
+	}
+
+	return "[Vol " + strconv.Itoa(sink.VolumePercent()) + "%[]"
+}
+
+// changeVolume raises or lowers the sink volume of the selected device.
+func changeVolume(raise bool) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	sink, ok := getDeviceSink(device.Address)
+	if !ok {
+		return false
+	}
+
+	if err := sink.ChangeVolume(raise); err != nil {
+		ErrorMessage(err)
+		return false
+	}
+
+	return true
+}
+
+// toggleMute toggles the sink mute state of the selected device.
+func toggleMute() bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	sink, ok := getDeviceSink(device.Address)
+	if !ok {
+		return false
+	}
+
+	if err := sink.ToggleMute(); err != nil {
+		ErrorMessage(err)
+		return false
+	}
+
+	setMenuItemToggle("device", cmd.KeyDeviceVolumeMute, !sink.Mute)
+
+	return true
+}
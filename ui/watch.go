@@ -1,5 +1,11 @@
 package ui
 
+import (
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+)
+
 // watchEvent listens to DBus events and passes them to
 // the event handlers.
 func watchEvent() {
@@ -11,5 +17,36 @@ func watchEvent() {
 
 		adapterEvent(signal, signalData)
 		deviceEvent(signal, signalData)
+		gattEvent(signal, signalData)
+		advertisementEvent(signal, signalData)
+		connectedProfilesEvent(signal)
+	}
+}
+
+// watchAudioEvent listens to pulseaudio events and passes them to
+// the audio event handlers. If the connection to the pulseaudio
+// server is lost, it is re-established.
+func watchAudioEvent() {
+	watcher, err := bluez.WatchAudio()
+	if err != nil {
+		return
+	}
+
+	for {
+		refreshVolumes()
+
+		for event := range watcher.Events {
+			audioEvent(event)
+		}
+
+		watcher.Close()
+
+		for {
+			time.Sleep(5 * time.Second)
+
+			if watcher, err = bluez.WatchAudio(); err == nil {
+				break
+			}
+		}
 	}
 }